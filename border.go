@@ -0,0 +1,70 @@
+package tui
+
+// The box-drawing characters used for a border.
+type BorderStyle int
+
+const (
+	BorderNone BorderStyle = iota
+	BorderSingle
+	BorderDouble
+	BorderRounded
+)
+
+// Horizontal, vertical, top-left, top-right, bottom-left and bottom-right
+var borderChars = map[BorderStyle][6]rune{
+	BorderSingle:  {'─', '│', '┌', '┐', '└', '┘'},
+	BorderDouble:  {'═', '║', '╔', '╗', '╚', '╝'},
+	BorderRounded: {'─', '│', '╭', '╮', '╰', '╯'},
+}
+
+// Draw a border around the edge of the DrawTarget, with an optional title in
+// the top edge. Titles which don't fit are cut short. Nothing is drawn for
+// BorderNone.
+func (target *DrawTarget) Border(border BorderStyle, style Style,
+	title string) {
+	chars, ok := borderChars[border]
+	if !ok || target.Width < 2 || target.Height < 2 {
+		return
+	}
+
+	right := target.Width - 1
+	bottom := target.Height - 1
+
+	for x := 1; x < right; x++ {
+		target.SetCell(x, 0, style.Fg, style.Bg, chars[0])
+		target.SetCell(x, bottom, style.Fg, style.Bg, chars[0])
+	}
+
+	for y := 1; y < bottom; y++ {
+		target.SetCell(0, y, style.Fg, style.Bg, chars[1])
+		target.SetCell(right, y, style.Fg, style.Bg, chars[1])
+	}
+
+	target.SetCell(0, 0, style.Fg, style.Bg, chars[2])
+	target.SetCell(right, 0, style.Fg, style.Bg, chars[3])
+	target.SetCell(0, bottom, style.Fg, style.Bg, chars[4])
+	target.SetCell(right, bottom, style.Fg, style.Bg, chars[5])
+
+	// Leave a corner and a line on each side of the title
+	room := target.Width - 6
+
+	if title == "" || room < 1 {
+		return
+	}
+
+	runes := []rune(title)
+	if len(runes) > room {
+		runes = runes[:room]
+	}
+
+	target.Print(2, 0, style.Fg, style.Bg, " %s ", string(runes))
+}
+
+// Pick the border style for a control depending on whether it has focus.
+func borderStyle(focus bool) Style {
+	if focus {
+		return theme.FocusedBorder
+	}
+
+	return theme.Border
+}
@@ -8,10 +8,15 @@ import (
 type ButtonClickEvent func(*Button)
 
 type Button struct {
+	Invalidation
+
 	Bounds       Rect
 	Text         string
 	focus        bool
 	ClickHandler ButtonClickEvent
+
+	// Drawn around the edge, with the text inside it
+	Border BorderStyle
 }
 
 func (b *Button) GetBounds() *Rect {
@@ -19,22 +24,34 @@ func (b *Button) GetBounds() *Rect {
 }
 
 func (b *Button) Draw(target *DrawTarget) {
-	target.Print(2, 1, termbox.ColorWhite, termbox.ColorBlack, b.Text)
+	target.Border(b.Border, borderStyle(b.focus), "")
+
+	style := focusStyle(b.focus)
+	target.Print(2, 1, style.Fg, style.Bg, b.Text)
 
 	if b.focus {
-		termbox.SetCursor(b.Bounds.Left+1, b.Bounds.Top+1)
+		target.SetCursor(1, 1)
 	}
 }
 
 func (b *Button) SetFocus() {
 	b.focus = true
+	b.Invalidate()
 }
 
 func (b *Button) UnsetFocus() {
 	b.focus = false
+	b.Invalidate()
 }
 
 func (b *Button) HandleEvent(ev escapebox.Event) bool {
+	if leftClick(ev) {
+		if b.ClickHandler != nil {
+			b.ClickHandler(b)
+		}
+		return true
+	}
+
 	switch ev.Type {
 	case termbox.EventKey:
 		switch ev.Key {
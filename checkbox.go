@@ -6,6 +6,8 @@ import (
 )
 
 type CheckBox struct {
+	Invalidation
+
 	Bounds  Rect
 	Text    string
 	Checked bool
@@ -23,28 +25,37 @@ func (c *CheckBox) Draw(target *DrawTarget) {
 		checkContent = "X"
 	}
 
-	target.Print(0, 0, termbox.ColorWhite, termbox.ColorBlack,
-		"[%s] %s", checkContent, c.Text)
+	style := focusStyle(c.focus)
+	target.Print(0, 0, style.Fg, style.Bg, "[%s] %s", checkContent, c.Text)
 
 	if c.focus {
-		termbox.SetCursor(c.Bounds.Left+1, c.Bounds.Top)
+		target.SetCursor(1, 0)
 	}
 }
 
 func (c *CheckBox) SetFocus() {
 	c.focus = true
+	c.Invalidate()
 }
 
 func (c *CheckBox) UnsetFocus() {
 	c.focus = false
+	c.Invalidate()
 }
 
 func (c *CheckBox) HandleEvent(ev escapebox.Event) bool {
+	if leftClick(ev) {
+		c.Checked = !c.Checked
+		c.Invalidate()
+		return true
+	}
+
 	switch ev.Type {
 	case termbox.EventKey:
 		switch ev.Key {
 		case termbox.KeySpace:
 			c.Checked = !c.Checked
+			c.Invalidate()
 			return true
 		}
 	}
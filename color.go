@@ -0,0 +1,315 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/nsf/termbox-go"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// A Color is either the terminal's default color, an entry in the 256-color
+// palette or a 24-bit RGB value. Attributes like AttrBold can be combined
+// with it using bitwise OR.
+//
+// Colors are downgraded to what the terminal supports when drawn: see
+// ColorMode.
+type Color uint32
+
+const (
+	colorValueMask Color = 0xffffff
+	colorKindMask  Color = 3 << 24
+	colorPalette   Color = 1 << 24
+	colorRGB       Color = 2 << 24
+	attrMask       Color = ^(colorValueMask | colorKindMask)
+)
+
+// The terminal's default foreground or background color.
+const ColorDefault Color = 0
+
+// The 16 standard palette colors. Their actual appearance depends on the
+// terminal's configuration.
+const (
+	ColorBlack Color = colorPalette | iota
+	ColorRed
+	ColorGreen
+	ColorYellow
+	ColorBlue
+	ColorMagenta
+	ColorCyan
+	ColorWhite
+	ColorDarkGray
+	ColorLightRed
+	ColorLightGreen
+	ColorLightYellow
+	ColorLightBlue
+	ColorLightMagenta
+	ColorLightCyan
+	ColorLightGray
+)
+
+// Text attributes. Not every terminal supports all of them.
+const (
+	AttrBold Color = 1 << (iota + 26)
+	AttrDim
+	AttrUnderline
+	AttrCursive
+	AttrBlink
+	AttrReverse
+)
+
+// An entry in the 256-color palette.
+func PaletteColor(index uint8) Color {
+	return colorPalette | Color(index)
+}
+
+// A 24-bit color.
+func RGB(r, g, b uint8) Color {
+	return colorRGB | Color(r)<<16 | Color(g)<<8 | Color(b)
+}
+
+// Parse a color written as "#rrggbb" or "#rgb".
+func HexColor(hex string) (Color, error) {
+	invalid := fmt.Errorf("Invalid hex color %q", hex)
+
+	if !strings.HasPrefix(hex, "#") {
+		return ColorDefault, invalid
+	}
+
+	digits := hex[1:]
+
+	if len(digits) == 3 {
+		digits = string([]byte{digits[0], digits[0], digits[1],
+			digits[1], digits[2], digits[2]})
+	}
+
+	if len(digits) != 6 {
+		return ColorDefault, invalid
+	}
+
+	value, err := strconv.ParseUint(digits, 16, 32)
+	if err != nil {
+		return ColorDefault, invalid
+	}
+
+	return colorRGB | Color(value), nil
+}
+
+// The color without any attributes.
+func (c Color) color() Color {
+	return c &^ attrMask
+}
+
+func (c Color) isDefault() bool {
+	return c.color() == ColorDefault
+}
+
+func (c Color) isPalette() bool {
+	return c&colorKindMask == colorPalette
+}
+
+func (c Color) isRGB() bool {
+	return c&colorKindMask == colorRGB
+}
+
+// The palette index of a palette color.
+func (c Color) index() int {
+	return int(c & 0xff)
+}
+
+// The red, green and blue components of the color. Palette colors are
+// converted using the standard xterm palette. The default color has no
+// components and returns black.
+func (c Color) RGB() (uint8, uint8, uint8) {
+	if c.isPalette() {
+		return paletteRGB(c.index())
+	}
+
+	return uint8(c >> 16), uint8(c >> 8), uint8(c)
+}
+
+func (c Color) String() string {
+	var name string
+
+	switch {
+	case c.isPalette():
+		name = fmt.Sprintf("palette(%d)", c.index())
+	case c.isRGB():
+		r, g, b := c.RGB()
+		name = fmt.Sprintf("#%02x%02x%02x", r, g, b)
+	default:
+		name = "default"
+	}
+
+	for _, a := range attributes {
+		if c&a.attr != 0 {
+			name += "+" + a.name
+		}
+	}
+
+	return name
+}
+
+var attributes = []struct {
+	attr    Color
+	termbox termbox.Attribute
+	name    string
+}{
+	{AttrBold, termbox.AttrBold, "bold"},
+	{AttrDim, termbox.AttrDim, "dim"},
+	{AttrUnderline, termbox.AttrUnderline, "underline"},
+	{AttrCursive, termbox.AttrCursive, "cursive"},
+	{AttrBlink, termbox.AttrBlink, "blink"},
+	{AttrReverse, termbox.AttrReverse, "reverse"},
+}
+
+// How many colors the terminal can display.
+type ColorMode int
+
+const (
+	// The 8 basic colors. Bright colors are shown as their basic version.
+	ColorMode8 ColorMode = iota
+
+	// The 16 standard colors. Bright foregrounds are shown as bold.
+	ColorMode16
+
+	// The 256-color palette.
+	ColorMode256
+
+	// 24-bit RGB ("truecolor").
+	ColorModeTrue
+)
+
+// Guess the terminal's color support from the COLORTERM and TERM environment
+// variables.
+func DetectColorMode() ColorMode {
+	colorTerm := strings.ToLower(os.Getenv("COLORTERM"))
+	if colorTerm == "truecolor" || colorTerm == "24bit" {
+		return ColorModeTrue
+	}
+
+	term := strings.ToLower(os.Getenv("TERM"))
+
+	switch {
+	case strings.Contains(term, "truecolor") ||
+		strings.Contains(term, "direct"):
+		return ColorModeTrue
+	case strings.Contains(term, "256color"):
+		return ColorMode256
+	case strings.Contains(term, "16color") || term == "linux" ||
+		strings.HasPrefix(term, "xterm") ||
+		strings.HasPrefix(term, "screen") ||
+		strings.HasPrefix(term, "tmux") ||
+		strings.HasPrefix(term, "rxvt"):
+		return ColorMode16
+	}
+
+	return ColorMode8
+}
+
+// The RGB values of the 16 standard colors in xterm's default palette
+var standardColors = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// Intensities of the six steps of the 6x6x6 color cube (indices 16-231)
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+func paletteRGB(index int) (uint8, uint8, uint8) {
+	switch {
+	case index < 16:
+		c := standardColors[index]
+		return c[0], c[1], c[2]
+	case index < 232:
+		index -= 16
+		return cubeLevels[index/36], cubeLevels[index/6%6],
+			cubeLevels[index%6]
+	default:
+		gray := uint8(8 + (index-232)*10)
+		return gray, gray, gray
+	}
+}
+
+func colorDistance(r1, g1, b1, r2, g2, b2 uint8) int {
+	dr := int(r1) - int(r2)
+	dg := int(g1) - int(g2)
+	db := int(b1) - int(b2)
+	return dr*dr + dg*dg + db*db
+}
+
+// Find the palette index in [first, last] which looks closest to the color.
+func nearestPaletteIndex(c Color, first, last int) int {
+	r, g, b := c.RGB()
+
+	best := first
+	bestDistance := -1
+
+	for i := first; i <= last; i++ {
+		pr, pg, pb := paletteRGB(i)
+		distance := colorDistance(r, g, b, pr, pg, pb)
+
+		if bestDistance == -1 || distance < bestDistance {
+			best = i
+			bestDistance = distance
+		}
+	}
+
+	return best
+}
+
+// Convert a color (and its attributes) to a termbox attribute for a terminal
+// with the given color support. The termbox output mode must match: see
+// termboxOutputMode.
+func (c Color) toTermbox(mode ColorMode, foreground bool) termbox.Attribute {
+	var ret termbox.Attribute
+
+	if !c.isDefault() {
+		switch mode {
+		case ColorModeTrue:
+			ret = termbox.RGBToAttribute(c.RGB())
+		case ColorMode256:
+			index := c.index()
+			if !c.isPalette() {
+				index = nearestPaletteIndex(c, 16, 255)
+			}
+			ret = termbox.Attribute(index + 1)
+		default:
+			index := c.index()
+			if !c.isPalette() || index >= 16 {
+				index = nearestPaletteIndex(c, 0, 15)
+			}
+
+			// Bright colors are only available as bold foregrounds
+			if index >= 8 {
+				index -= 8
+				if foreground && mode == ColorMode16 {
+					ret |= termbox.AttrBold
+				}
+			}
+
+			ret |= termbox.Attribute(index + 1)
+		}
+	}
+
+	for _, a := range attributes {
+		if c&a.attr != 0 {
+			ret |= a.termbox
+		}
+	}
+
+	return ret
+}
+
+func termboxOutputMode(mode ColorMode) termbox.OutputMode {
+	switch mode {
+	case ColorModeTrue:
+		return termbox.OutputRGB
+	case ColorMode256:
+		return termbox.Output256
+	}
+
+	return termbox.OutputNormal
+}
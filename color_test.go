@@ -0,0 +1,168 @@
+package tui_test
+
+import (
+	"github.com/briansteffens/tui"
+	"github.com/nsf/termbox-go"
+	"testing"
+)
+
+func TestHexColor(t *testing.T) {
+	tests := []struct {
+		hex      string
+		expected tui.Color
+		ok       bool
+	}{
+		{"#ff8000", tui.RGB(255, 128, 0), true},
+		{"#FF8000", tui.RGB(255, 128, 0), true},
+		{"#f80", tui.RGB(255, 136, 0), true},
+		{"#000000", tui.RGB(0, 0, 0), true},
+		{"ff8000", tui.ColorDefault, false},
+		{"#ff800", tui.ColorDefault, false},
+		{"#ff80000", tui.ColorDefault, false},
+		{"#gg8000", tui.ColorDefault, false},
+		{"#", tui.ColorDefault, false},
+		{"", tui.ColorDefault, false},
+	}
+
+	for _, test := range tests {
+		actual, err := tui.HexColor(test.hex)
+
+		if (err == nil) != test.ok || actual != test.expected {
+			t.Errorf("HexColor(%q): expected %v (ok %t), got %v (%v)",
+				test.hex, test.expected, test.ok, actual, err)
+		}
+	}
+
+	// Black is a color of its own, not the terminal's default
+	if black, _ := tui.HexColor("#000"); black == tui.ColorDefault {
+		t.Errorf("expected #000 to differ from the default color")
+	}
+}
+
+func TestDetectColorMode(t *testing.T) {
+	tests := []struct {
+		colorTerm string
+		term      string
+		expected  tui.ColorMode
+	}{
+		{"truecolor", "xterm-256color", tui.ColorModeTrue},
+		{"24bit", "", tui.ColorModeTrue},
+		{"TrueColor", "vt100", tui.ColorModeTrue},
+		{"", "xterm-direct", tui.ColorModeTrue},
+		{"", "xterm-256color", tui.ColorMode256},
+		{"", "tmux-256color", tui.ColorMode256},
+		{"yes", "screen-256color", tui.ColorMode256},
+		{"", "xterm", tui.ColorMode16},
+		{"", "screen", tui.ColorMode16},
+		{"", "rxvt-unicode", tui.ColorMode16},
+		{"", "linux", tui.ColorMode16},
+		{"", "vt100", tui.ColorMode8},
+		{"", "dumb", tui.ColorMode8},
+		{"", "", tui.ColorMode8},
+	}
+
+	for _, test := range tests {
+		t.Setenv("COLORTERM", test.colorTerm)
+		t.Setenv("TERM", test.term)
+
+		if actual := tui.DetectColorMode(); actual != test.expected {
+			t.Errorf("COLORTERM=%q TERM=%q: expected mode %d, got %d",
+				test.colorTerm, test.term, test.expected, actual)
+		}
+	}
+}
+
+func TestNearestPaletteIndex(t *testing.T) {
+	tests := []struct {
+		color       tui.Color
+		first, last int
+		expected    int
+	}{
+		// The 6x6x6 cube
+		{tui.RGB(0, 0, 0), 16, 255, 16},
+		{tui.RGB(255, 0, 0), 16, 255, 196},
+		{tui.RGB(95, 135, 175), 16, 255, 67},
+		{tui.RGB(250, 250, 250), 16, 255, 231},
+
+		// Grays closer to the gray ramp than to the cube
+		{tui.RGB(8, 8, 8), 16, 255, 232},
+		{tui.RGB(128, 128, 128), 16, 255, 244},
+		{tui.RGB(238, 238, 238), 16, 255, 255},
+
+		// The 16 standard colors, including grays
+		{tui.RGB(255, 0, 0), 0, 15, 9},
+		{tui.RGB(200, 0, 0), 0, 15, 1},
+		{tui.RGB(128, 128, 128), 0, 15, 8},
+		{tui.RGB(200, 200, 200), 0, 15, 7},
+		{tui.RGB(30, 30, 30), 0, 15, 0},
+		{tui.PaletteColor(196), 0, 15, 9},
+	}
+
+	for _, test := range tests {
+		actual := tui.NearestPaletteIndex(test.color, test.first,
+			test.last)
+
+		if actual != test.expected {
+			t.Errorf("%v in [%d, %d]: expected %d, got %d",
+				test.color, test.first, test.last, test.expected,
+				actual)
+		}
+	}
+}
+
+func TestColorDowngrade(t *testing.T) {
+	// termbox numbers palette colors from 1, with 0 as the default
+	palette := func(index int) termbox.Attribute {
+		return termbox.Attribute(index + 1)
+	}
+
+	tests := []struct {
+		color      tui.Color
+		mode       tui.ColorMode
+		foreground bool
+		expected   termbox.Attribute
+	}{
+		{tui.RGB(1, 2, 3), tui.ColorModeTrue, true,
+			termbox.RGBToAttribute(1, 2, 3)},
+		{tui.ColorRed, tui.ColorModeTrue, true,
+			termbox.RGBToAttribute(205, 0, 0)},
+
+		{tui.RGB(255, 0, 0), tui.ColorMode256, true, palette(196)},
+		{tui.RGB(128, 128, 128), tui.ColorMode256, false, palette(244)},
+		{tui.PaletteColor(100), tui.ColorMode256, true, palette(100)},
+		{tui.ColorLightRed, tui.ColorMode256, true, palette(9)},
+
+		// Bright colors become bold foregrounds, or plain backgrounds
+		{tui.RGB(255, 0, 0), tui.ColorMode16, true,
+			palette(1) | termbox.AttrBold},
+		{tui.RGB(255, 0, 0), tui.ColorMode16, false, palette(1)},
+		{tui.RGB(128, 128, 128), tui.ColorMode16, true,
+			palette(0) | termbox.AttrBold},
+		{tui.RGB(200, 200, 200), tui.ColorMode16, false, palette(7)},
+		{tui.PaletteColor(196), tui.ColorMode16, true,
+			palette(1) | termbox.AttrBold},
+		{tui.ColorBlue, tui.ColorMode16, true, palette(4)},
+
+		// Bright colors lose their brightness
+		{tui.RGB(255, 0, 0), tui.ColorMode8, true, palette(1)},
+		{tui.RGB(128, 128, 128), tui.ColorMode8, true, palette(0)},
+		{tui.ColorLightCyan, tui.ColorMode8, false, palette(6)},
+
+		// Attributes and the default color survive every mode
+		{tui.ColorDefault, tui.ColorMode256, true, 0},
+		{tui.ColorDefault | tui.AttrBold, tui.ColorMode8, true,
+			termbox.AttrBold},
+		{tui.RGB(255, 0, 0) | tui.AttrUnderline, tui.ColorMode256, true,
+			palette(196) | termbox.AttrUnderline},
+	}
+
+	for _, test := range tests {
+		actual := test.color.ToTermbox(test.mode, test.foreground)
+
+		if actual != test.expected {
+			t.Errorf("%v in mode %d (foreground %t): expected %d, "+
+				"got %d", test.color, test.mode, test.foreground,
+				test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,442 @@
+package tui
+
+import (
+	"github.com/briansteffens/escapebox"
+	"github.com/nsf/termbox-go"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+type ComboChangedEvent func(c *ComboBox, value string)
+
+// How many items a ComboBox's list shows at once, unless MaxVisible is set
+const defaultComboVisible = 8
+
+// A ComboBox is a TextBox with a list of items to pick from. Typing opens the
+// list, showing only the items which match the text: first those starting
+// with it, then those containing it, then those containing its characters in
+// order. Down opens the list without typing, Up and Down move through it,
+// Enter picks the highlighted item and Esc closes the list.
+//
+// Unless ListOnly is set, any text can be entered. Either way OnChanged is
+// called with the new value when an item is picked, or the text is accepted
+// with Enter or by moving focus away.
+type ComboBox struct {
+	TextBox
+
+	Items []string
+
+	// Only allow values from Items. Text which doesn't match an item is
+	// reverted when it's accepted.
+	ListOnly bool
+
+	// How many items the list shows at once. Zero means 8.
+	MaxVisible int
+
+	// Called with the value when it changes
+	OnChanged ComboChangedEvent
+
+	// The value when the ComboBox was focused or an item was last picked
+	chosen string
+
+	container *Container
+	list      *comboList
+}
+
+// An item of a ComboBox which matches the text, and the positions of the
+// matching characters
+type comboMatch struct {
+	item      int
+	positions []int
+}
+
+// How well an item matches the text of a ComboBox, best first.
+const (
+	matchPrefix = iota
+	matchSubstring
+	matchFuzzy
+	matchNone
+)
+
+// Match filter against item, ignoring case. Returns the positions of the
+// matching characters in item and how well it matches.
+func matchItem(filter, item string) ([]int, int) {
+	f := []rune(filter)
+	t := []rune(item)
+
+	for i := range f {
+		f[i] = unicode.ToLower(f[i])
+	}
+
+	for i := range t {
+		t[i] = unicode.ToLower(t[i])
+	}
+
+	if len(f) == 0 {
+		return []int{}, matchPrefix
+	}
+
+	// Look for filter as a whole first
+	for start := 0; start+len(f) <= len(t); start++ {
+		if string(t[start:start+len(f)]) != string(f) {
+			continue
+		}
+
+		positions := make([]int, len(f))
+		for i := range f {
+			positions[i] = start + i
+		}
+
+		if start == 0 {
+			return positions, matchPrefix
+		}
+
+		return positions, matchSubstring
+	}
+
+	// Then for its characters in order, with anything in between
+	positions := []int{}
+
+	for pos, r := range t {
+		if len(positions) < len(f) && r == f[len(positions)] {
+			positions = append(positions, pos)
+		}
+	}
+
+	if len(positions) < len(f) {
+		return nil, matchNone
+	}
+
+	return positions, matchFuzzy
+}
+
+func (c *ComboBox) placedIn(container *Container) {
+	c.container = container
+}
+
+// The items matching the text, best matches first.
+func (c *ComboBox) matches() []comboMatch {
+	ret := []comboMatch{}
+	ranks := []int{}
+
+	for i, item := range c.Items {
+		positions, rank := matchItem(c.Value, item)
+		if rank == matchNone {
+			continue
+		}
+
+		ret = append(ret, comboMatch{i, positions})
+		ranks = append(ranks, rank)
+	}
+
+	sort.Stable(&comboMatches{ret, ranks})
+
+	return ret
+}
+
+// Sorts matches by rank, keeping them in the same order otherwise
+type comboMatches struct {
+	matches []comboMatch
+	ranks   []int
+}
+
+func (m *comboMatches) Len() int {
+	return len(m.matches)
+}
+
+func (m *comboMatches) Less(i, j int) bool {
+	return m.ranks[i] < m.ranks[j]
+}
+
+func (m *comboMatches) Swap(i, j int) {
+	m.matches[i], m.matches[j] = m.matches[j], m.matches[i]
+	m.ranks[i], m.ranks[j] = m.ranks[j], m.ranks[i]
+}
+
+// Show the list of matching items below the ComboBox, or above it if there
+// isn't room below. The list is closed if nothing matches.
+func (c *ComboBox) open() {
+	if c.container == nil {
+		return
+	}
+
+	bounds, ok := c.container.screenBounds(c)
+	matches := c.matches()
+
+	if !ok || len(matches) == 0 {
+		c.close()
+		return
+	}
+
+	visible := c.MaxVisible
+	if visible <= 0 {
+		visible = defaultComboVisible
+	}
+
+	height := min(len(matches), visible) + 2
+	root := c.container.root()
+
+	listBounds := Rect{
+		Left:   bounds.Left,
+		Top:    bounds.Bottom() + 1,
+		Width:  bounds.Width,
+		Height: height,
+	}
+
+	if listBounds.Bottom() >= root.Bounds.Height && bounds.Top >= height {
+		listBounds.Top = bounds.Top - height
+	}
+
+	if c.list == nil {
+		c.list = &comboList{combo: c}
+	} else if listBounds != c.list.Bounds {
+		// Uncover what the list covered before
+		root.Invalidate()
+	}
+
+	c.list.Bounds = listBounds
+	c.list.matches = matches
+	c.list.move(-c.list.cursor)
+
+	c.container.showPopup(c.list)
+}
+
+// Hide the list.
+func (c *ComboBox) close() {
+	if c.list == nil {
+		return
+	}
+
+	if c.container != nil {
+		c.container.hidePopup(c.list)
+	}
+
+	c.list = nil
+}
+
+// Set the text to value, close the list and call OnChanged if the value
+// changed.
+func (c *ComboBox) choose(value string) {
+	// Put the caret after the text
+	c.Value = value
+	c.cursor = len(value)
+	c.scroll = max(0, c.cursor-c.maxVisibleChars()+1)
+	c.Invalidate()
+
+	c.close()
+
+	if value == c.chosen {
+		return
+	}
+
+	c.chosen = value
+
+	if c.OnChanged != nil {
+		c.OnChanged(c, value)
+	}
+}
+
+// Accept the text as the value. With ListOnly, text which doesn't match an
+// item is reverted.
+func (c *ComboBox) accept() {
+	if !c.ListOnly {
+		c.choose(c.Value)
+		return
+	}
+
+	for _, item := range c.Items {
+		if strings.EqualFold(item, c.Value) {
+			c.choose(item)
+			return
+		}
+	}
+
+	c.choose(c.chosen)
+}
+
+func (c *ComboBox) SetFocus() {
+	c.chosen = c.Value
+	c.TextBox.SetFocus()
+}
+
+// Close the list and accept the text.
+func (c *ComboBox) UnsetFocus() {
+	c.close()
+
+	if c.Value != c.chosen {
+		c.accept()
+	}
+
+	c.TextBox.UnsetFocus()
+}
+
+func (c *ComboBox) Draw(target *DrawTarget) {
+	c.TextBox.Draw(target)
+
+	style := focusStyle(c.focus)
+	target.SetCell(c.Bounds.Width-1, 1, style.Fg, style.Bg, '▾')
+}
+
+func (c *ComboBox) HandleEvent(ev escapebox.Event) bool {
+	if leftClick(ev) {
+		if c.list == nil {
+			c.open()
+		} else {
+			c.close()
+		}
+	}
+
+	if ev.Type != termbox.EventKey {
+		return c.TextBox.HandleEvent(ev)
+	}
+
+	switch ev.Key {
+	case termbox.KeyArrowDown:
+		if c.list == nil {
+			c.open()
+		} else {
+			c.list.move(1)
+		}
+		return true
+	case termbox.KeyArrowUp:
+		if c.list == nil {
+			return false
+		}
+
+		c.list.move(-1)
+		return true
+	case termbox.KeyPgup, termbox.KeyPgdn:
+		if c.list == nil {
+			return false
+		}
+
+		page := max(1, c.list.visible()-1)
+		if ev.Key == termbox.KeyPgup {
+			page = -page
+		}
+
+		c.list.move(page)
+		return true
+	case termbox.KeyEnter:
+		if c.list != nil {
+			c.choose(c.Items[c.list.matches[c.list.cursor].item])
+		} else {
+			c.accept()
+		}
+		return true
+	case termbox.KeyEsc:
+		if c.list == nil {
+			return false
+		}
+
+		c.close()
+		return true
+	}
+
+	oldValue := c.Value
+	handled := c.TextBox.HandleEvent(ev)
+
+	if c.Value != oldValue {
+		c.open()
+		handled = true
+	}
+
+	return handled
+}
+
+// The list of a ComboBox, shown as a popup.
+type comboList struct {
+	Invalidation
+
+	Bounds  Rect
+	combo   *ComboBox
+	matches []comboMatch
+	cursor  int
+	scroll  int
+}
+
+func (l *comboList) GetBounds() *Rect {
+	return &l.Bounds
+}
+
+// How many items fit inside the border.
+func (l *comboList) visible() int {
+	return max(0, l.Bounds.Height-2)
+}
+
+// Move the highlight by delta items, scrolling to keep it in view.
+func (l *comboList) move(delta int) {
+	l.cursor = max(0, min(l.cursor+delta, len(l.matches)-1))
+	l.scroll = max(0, min(l.scroll, len(l.matches)-l.visible()))
+
+	if l.cursor < l.scroll {
+		l.scroll = l.cursor
+	}
+
+	if l.cursor >= l.scroll+l.visible() {
+		l.scroll = l.cursor - l.visible() + 1
+	}
+
+	l.Invalidate()
+}
+
+func (l *comboList) Draw(target *DrawTarget) {
+	target.Border(BorderSingle, theme.Border, "")
+
+	width := l.Bounds.Width - 2
+	last := min(len(l.matches), l.scroll+l.visible())
+
+	for index := l.scroll; index < last; index++ {
+		match := l.matches[index]
+
+		style := theme.Normal
+		if index == l.cursor {
+			style = theme.Selected
+		}
+
+		matched := map[int]bool{}
+		for _, pos := range match.positions {
+			matched[pos] = true
+		}
+
+		runes := []rune(l.combo.Items[match.item])
+		y := index - l.scroll + 1
+
+		for x := 0; x < width; x++ {
+			ch := ' '
+			fg := style.Fg
+
+			if x < len(runes) {
+				ch = runes[x]
+			}
+
+			if matched[x] {
+				fg = theme.Match.Fg
+			}
+
+			target.SetCell(x+1, y, fg, style.Bg, ch)
+		}
+	}
+}
+
+// Scroll with the mouse wheel, or pick an item by clicking on it.
+func (l *comboList) HandleEvent(ev escapebox.Event) bool {
+	if direction := wheelDirection(ev); direction != 0 {
+		l.move(direction * wheelLines)
+		return true
+	}
+
+	if !leftClick(ev) {
+		return false
+	}
+
+	index := l.scroll + ev.MouseY - 1
+	if ev.MouseY < 1 || ev.MouseY > l.visible() || index >= len(l.matches) {
+		return false
+	}
+
+	l.combo.choose(l.combo.Items[l.matches[index].item])
+
+	return true
+}
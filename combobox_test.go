@@ -0,0 +1,84 @@
+package tui_test
+
+import (
+	"fmt"
+	"github.com/briansteffens/tui"
+	"github.com/briansteffens/tui/tuitest"
+	"github.com/nsf/termbox-go"
+	"testing"
+)
+
+func TestComboBoxFiltering(t *testing.T) {
+	changes := []string{}
+
+	newComboContainer := func(listOnly bool) (*tui.Container,
+		*tui.ComboBox) {
+		combo := &tui.ComboBox{
+			TextBox: tui.TextBox{
+				Bounds: tui.Rect{Width: 20, Height: 3},
+				Border: tui.BorderSingle,
+			},
+			Items:    []string{"mysql", "postgres", "sqlite", "mssql"},
+			ListOnly: listOnly,
+			OnChanged: func(c *tui.ComboBox, value string) {
+				changes = append(changes, value)
+			},
+		}
+
+		// Focus starts on the second control
+		other := &tui.TextBox{Bounds: tui.Rect{Top: 3, Width: 20,
+			Height: 3}}
+
+		return &tui.Container{
+			Controls:            []tui.Control{other, combo},
+			KeyBindingFocusNext: tui.KeyBinding{Key: termbox.KeyTab},
+		}, combo
+	}
+
+	// Items starting with the text come first, then the ones containing
+	// it, drawn over the TextBox below
+	c, _ := newComboContainer(false)
+	s := tuitest.Run(t, c, 20, 10, tuitest.Type("sq")...)
+
+	tuitest.AssertLine(t, s, 3, "┌──────────────────┐")
+	tuitest.AssertLine(t, s, 4, "│sqlite            │")
+	tuitest.AssertLine(t, s, 5, "│mysql             │")
+	tuitest.AssertLine(t, s, 6, "│mssql             │")
+
+	selected := tui.CurrentTheme().Selected
+	tuitest.AssertStyle(t, s, 2, 4, tui.CurrentTheme().Match.Fg,
+		selected.Bg)
+	tuitest.AssertStyle(t, s, 3, 4, selected.Fg, selected.Bg)
+
+	c, combo := newComboContainer(false)
+	events := append(tuitest.Type("sq"), tuitest.Key(termbox.KeyArrowDown),
+		tuitest.Key(termbox.KeyEnter))
+	s = tuitest.Run(t, c, 20, 10, events...)
+
+	tuitest.AssertLine(t, s, 4, "")
+
+	if combo.Value != "mysql" || fmt.Sprint(changes) != "[mysql]" {
+		t.Errorf("expected mysql to be chosen, got %q and %v",
+			combo.Value, changes)
+	}
+
+	// Fuzzy matches highlight the characters which matched. Text which
+	// isn't an item is reverted when focus moves away.
+	c, combo = newComboContainer(true)
+	s = tuitest.Run(t, c, 20, 10, tuitest.Type("pgs")...)
+
+	tuitest.AssertLine(t, s, 4, "│postgres          │")
+
+	for _, x := range []int{1, 5, 8} {
+		tuitest.AssertStyle(t, s, x, 4, tui.CurrentTheme().Match.Fg,
+			selected.Bg)
+	}
+
+	// Starting MainLoop again moves focus away
+	tuitest.Run(t, c, 20, 10)
+
+	if combo.Value != "" || len(changes) != 1 {
+		t.Errorf("expected the text to be reverted, got %q and %v",
+			combo.Value, changes)
+	}
+}
@@ -1,39 +1,377 @@
 package tui
 
 import (
+	"fmt"
 	"github.com/briansteffens/escapebox"
+	"github.com/nsf/termbox-go"
 )
 
 type ResizeEvent func()
 type EventHandler func(c *Container, ev escapebox.Event) bool
 
+// A Container holds other controls and passes focus and input on to them.
+// The root Container is given to MainLoop and fills the screen. Containers can
+// also be nested like any other control: the nested Container's controls are
+// positioned relative to its Bounds and take part in the root's focus
+// traversal.
 type Container struct {
+	Invalidation
+
+	Bounds                  Rect
 	Controls                []Control
-	Focused                 Focusable
+	Layout                  Layout
 	ResizeHandler           ResizeEvent
 	Width                   int
 	Height                  int
 	KeyBindingFocusNext     KeyBinding
 	KeyBindingFocusPrevious KeyBinding
+	KeyBindingFocusUp       KeyBinding
+	KeyBindingFocusDown     KeyBinding
+	KeyBindingFocusLeft     KeyBinding
+	KeyBindingFocusRight    KeyBinding
 	KeyBindingExit          KeyBinding
-	HandleEvent             EventHandler
+
+	// Window bindings of the root Container: focus the next Window, close
+	// the focused one, or move or resize it with the arrow keys until Enter
+	// or Esc is pressed
+	KeyBindingWindowNext   KeyBinding
+	KeyBindingWindowClose  KeyBinding
+	KeyBindingWindowMove   KeyBinding
+	KeyBindingWindowResize KeyBinding
+
+	// Called with events the focused control didn't handle. Handlers of
+	// nested Containers are called first, innermost to outermost.
+	EventHandler EventHandler
+
+	// The deepest focused control in the Container, if focus is inside it
+	Focused Focusable
+
+	// The Container this one is nested in, or nil for the root
+	parent *Container
+
+	// Windows shown on top of the root Container, in the order they were
+	// opened
+	windows []*Window
+
+	// What had focus among Controls when focus last moved into a Window
+	mainFocus Focusable
+
+	// The Window being moved or resized with the arrow keys, if any
+	windowMode windowMode
+	moving     *Window
+
+	// Dialogs shown on top of the root Container and its Windows, bottom to
+	// top
+	dialogs []*Dialog
+
+	// Controls like ComboBox lists shown above everything else, bottom to
+	// top
+	popups []popup
+
+	// The open menus, which take over the keyboard
+	menu *menuSession
+
+	// The control getting all mouse events while it's being dragged
+	captured mouseCapturer
+}
+
+func (c *Container) GetBounds() *Rect {
+	return &c.Bounds
+}
+
+func (c *Container) Children() []Control {
+	return c.Controls
 }
 
+// The outermost Container this one is nested in.
+func (c *Container) root() *Container {
+	for c.parent != nil {
+		c = c.parent
+	}
+
+	return c
+}
+
+// Move focus to f, which may be nil to unfocus everything. Focus is always
+// changed from the root so every Container on the way tracks it.
 func (c *Container) focus(f Focusable) {
+	if root := c.root(); root != c {
+		root.focus(f)
+		return
+	}
+
+	logger.Debug("focus changed", "from", fmt.Sprintf("%T", c.Focused),
+		"to", fmt.Sprintf("%T", f))
+
 	if c.Focused != nil {
+		c.rememberFocus()
 		c.Focused.UnsetFocus()
 	}
 
-	c.Focused = f
-	c.Focused.SetFocus()
+	c.track(f)
+
+	if f != nil {
+		f.SetFocus()
+	}
+}
+
+// Set Focused in the Container and all nested Containers to f, or to nil if f
+// isn't inside them. Returns whether f is inside the Container.
+func (c *Container) track(f Focusable) bool {
+	c.Focused = nil
+
+	for _, child := range c.Controls {
+		if trackFocus(child, c, f) {
+			c.Focused = f
+		}
+	}
+
+	for _, w := range c.windows {
+		w.parent = c
+		hadFocus := w.Focused != nil
+
+		if w.track(f) {
+			c.Focused = f
+
+			if !hadFocus {
+				w.Raise()
+			}
+		}
+
+		// Show whether the Window has focus in its border
+		if hadFocus != (w.Focused != nil) {
+			w.Invalidate()
+		}
+	}
+
+	for _, d := range c.dialogs {
+		d.parent = c
+		if d.track(f) {
+			c.Focused = f
+		}
+	}
+
+	return c.Focused != nil
+}
+
+// Parents which need to know about the focused control inside them, like
+// Frame and ScrollView
+type focusTracker interface {
+	focusChanged(f Focusable)
+}
+
+// Track focus through a control inside parent. Returns whether f is, or is
+// inside, ctrl.
+func trackFocus(ctrl Control, parent *Container, f Focusable) bool {
+	if p, ok := ctrl.(placed); ok {
+		p.placedIn(parent)
+	}
+
+	switch ctrl := ctrl.(type) {
+	case *Container:
+		ctrl.parent = parent
+		return ctrl.track(f)
+	case Parent:
+		found := false
+
+		for _, child := range ctrl.Children() {
+			if trackFocus(child, parent, f) {
+				found = true
+			}
+		}
+
+		if t, ok := ctrl.(focusTracker); ok {
+			if found {
+				t.focusChanged(f)
+			} else {
+				t.focusChanged(nil)
+			}
+		}
+
+		return found
+	}
+
+	return f != nil && ctrl == f
+}
+
+// Point nested Containers back at the Containers they're in.
+func (c *Container) adopt() {
+	c.track(c.Focused)
+}
+
+// The controls which belong directly to a Container: its Controls, with the
+// children of any Parents other than nested Containers in their place.
+func members(controls []Control) []Control {
+	ret := []Control{}
+
+	for _, ctrl := range controls {
+		_, nested := ctrl.(*Container)
+
+		if p, ok := ctrl.(Parent); ok && !nested {
+			ret = append(ret, members(p.Children())...)
+		} else {
+			ret = append(ret, ctrl)
+		}
+	}
+
+	return ret
+}
+
+// The controls which belong directly to the Container, followed by the
+// Containers of its open Windows and Dialogs.
+func (c *Container) contents() []Control {
+	return append(members(c.Controls), c.layers()...)
+}
+
+// The Containers of the open Windows and Dialogs.
+func (c *Container) layers() []Control {
+	ret := []Control{}
+
+	for _, w := range c.windows {
+		ret = append(ret, &w.Container)
+	}
+
+	for _, d := range c.dialogs {
+		ret = append(ret, &d.Container)
+	}
+
+	return ret
 }
 
+// All controls in focus order, descending into nested Containers and other
+// Parents.
+func leaves(controls []Control) []Control {
+	ret := []Control{}
+
+	for _, ctrl := range controls {
+		if p, ok := ctrl.(Parent); ok {
+			ret = append(ret, leaves(p.Children())...)
+		} else {
+			ret = append(ret, ctrl)
+		}
+	}
+
+	return ret
+}
+
+// The Parents between the Container and ctrl, outermost first, looking
+// inside open Windows and Dialogs too. Returns nil if ctrl isn't in the
+// Container.
+func (c *Container) ancestors(ctrl Control) []Control {
+	controls := append(append([]Control{}, c.Controls...), c.layers()...)
+
+	path, _ := ancestorsIn(controls, ctrl)
+	return path
+}
+
+func ancestorsIn(controls []Control, ctrl Control) ([]Control, bool) {
+	for _, child := range controls {
+		if child == ctrl {
+			return []Control{}, true
+		}
+
+		p, ok := child.(Parent)
+		if !ok {
+			continue
+		}
+
+		if path, found := ancestorsIn(p.Children(), ctrl); found {
+			return append([]Control{child}, path...), true
+		}
+	}
+
+	return nil, false
+}
+
+// Find where ctrl is among controls, including inside Parents, in the
+// coordinates of controls.
+func findBounds(controls []Control, ctrl Control) (Rect, bool) {
+	for _, c := range controls {
+		bounds := *c.GetBounds()
+
+		if c == ctrl {
+			return bounds, true
+		}
+
+		p, ok := c.(Parent)
+		if !ok {
+			continue
+		}
+
+		if found, ok := findBounds(p.Children(), ctrl); ok {
+			found.Left += bounds.Left
+			found.Top += bounds.Top
+			return found, true
+		}
+	}
+
+	return Rect{}, false
+}
+
+// Position the controls with the Container's Layout, if it has one, and
+// redraw everything. Nested Containers are arranged too. This happens
+// automatically when the root Container is resized, but needs to be called by
+// hand after changing Controls, Bounds or the Layout's settings.
+func (c *Container) Arrange() {
+	c.adopt()
+
+	if c.Layout != nil {
+		c.Layout.Arrange(Rect{Width: c.Bounds.Width,
+			Height: c.Bounds.Height}, c.Controls)
+	}
+
+	for _, w := range c.windows {
+		w.clamp()
+	}
+
+	for _, d := range c.dialogs {
+		d.center(c.Bounds)
+	}
+
+	for _, member := range c.contents() {
+		if nested, ok := member.(*Container); ok {
+			nested.Arrange()
+		}
+	}
+
+	c.Invalidate()
+}
+
+// The directional focus bindings, indexed by Direction.
+func (c *Container) directionBindings() []KeyBinding {
+	return []KeyBinding{c.KeyBindingFocusUp, c.KeyBindingFocusDown,
+		c.KeyBindingFocusLeft, c.KeyBindingFocusRight}
+}
+
+// Set the size of the root Container.
+func (c *Container) resize(width, height int) {
+	c.Width = width
+	c.Height = height
+	c.Bounds = Rect{Width: width, Height: height}
+}
+
+// Move focus to the next Focusable control, wrapping around at the end. Only
+// one RadioButton of each group is visited. On a nested Container this only
+// moves between the controls inside it.
 func (c *Container) FocusNext() {
-	currentIndex := 0
+	c.cycleFocus(1)
+}
+
+// Move focus to the previous Focusable control, wrapping around at the
+// start. On a nested Container this only moves between the controls inside
+// it.
+func (c *Container) FocusPrevious() {
+	c.cycleFocus(-1)
+}
+
+func (c *Container) cycleFocus(step int) {
+	controls := leaves(c.Controls)
 
 	// Find index of currently focused control
+	currentIndex := 0
+
 	if c.Focused != nil {
-		for index, ctrl := range c.Controls {
+		for index, ctrl := range controls {
 			if ctrl == c.Focused {
 				currentIndex = index
 				break
@@ -41,66 +379,325 @@ func (c *Container) FocusNext() {
 		}
 	}
 
-	// Scan list after focused control for another Focusable control
-	for i := currentIndex + 1; i < len(c.Controls); i++ {
-		f, ok := c.Controls[i].(Focusable)
-		if ok {
+	// Scan from the focused control for another Focusable control,
+	// looping around and ending back at the focused control.
+	for i := 1; i <= len(controls); i++ {
+		index := (currentIndex + i*step + len(controls)) % len(controls)
+
+		if f, ok := controls[index].(Focusable); ok && isTabStop(f) {
 			c.focus(f)
 			return
 		}
 	}
+}
+
+// Controls which FocusNext and FocusPrevious only visit some of the time,
+// like the RadioButtons of a group, which are visited as one
+type tabStopper interface {
+	tabStop() bool
+}
+
+// Check whether FocusNext and FocusPrevious should visit f.
+func isTabStop(f Focusable) bool {
+	t, ok := f.(tabStopper)
+	return !ok || t.tabStop()
+}
+
+// Focus the first control in the Container, unless focus is already inside
+// it.
+func (c *Container) SetFocus() {
+	if c.Focused != nil {
+		return
+	}
 
-	// Scan list before focused control (loop around)
-	for i := 0; i <= currentIndex; i++ {
-		f, ok := c.Controls[i].(Focusable)
-		if ok {
+	for _, ctrl := range leaves(c.Controls) {
+		if f, ok := ctrl.(Focusable); ok {
 			c.focus(f)
 			return
 		}
 	}
 }
 
-func (c *Container) FocusPrevious() {
-	currentIndex := 0
+// Remove focus from whatever control in the Container has it.
+func (c *Container) UnsetFocus() {
+	if c.Focused != nil {
+		c.focus(nil)
+	}
+}
+
+// Pass an event to the focused control, or for mouse events the control
+// under the pointer, then to EventHandler if it wasn't handled.
+func (c *Container) HandleEvent(ev escapebox.Event) bool {
+	if ev.Type == termbox.EventMouse {
+		return c.handleMouse(ev)
+	}
+
+	handled := false
 
-	// Find index of currently focused control
 	if c.Focused != nil {
-		for index, ctrl := range c.Controls {
-			if ctrl == c.Focused {
-				currentIndex = index
-				break
+		handled = c.Focused.HandleEvent(ev)
+	}
+
+	if !handled && c.EventHandler != nil {
+		handled = c.EventHandler(c, ev)
+	}
+
+	return handled
+}
+
+// A control under the mouse pointer, and the pointer's position in the
+// control's local coordinates
+type hit struct {
+	control Control
+	x, y    int
+}
+
+// Controls which want every mouse event while something is being dragged,
+// like the divider of a Split
+type mouseCapturer interface {
+	Focusable
+	capturesMouse() bool
+}
+
+// Parents which only show their children in part of their area, like
+// ScrollView
+type viewporter interface {
+	viewport() Rect
+}
+
+// Find the controls at (x, y), from the outermost to the deepest. Later
+// controls are on top of earlier ones.
+func controlsAt(controls []Control, x, y int) []hit {
+	for i := len(controls) - 1; i >= 0; i-- {
+		bounds := controls[i].GetBounds()
+
+		if !bounds.ContainsPoint(x, y) {
+			continue
+		}
+
+		x -= bounds.Left
+		y -= bounds.Top
+
+		ret := []hit{{controls[i], x, y}}
+
+		p, ok := controls[i].(Parent)
+		if !ok {
+			return ret
+		}
+
+		if v, ok := p.(viewporter); ok {
+			view := v.viewport()
+			if !view.ContainsPoint(x, y) {
+				return ret
 			}
 		}
+
+		return append(ret, controlsAt(p.Children(), x, y)...)
+	}
+
+	return nil
+}
+
+// Send a mouse event to the deepest Focusable control under the pointer,
+// translated into that control's local coordinates. If it isn't handled, the
+// Focusable Parents around it get a go, innermost first. Clicking a Focusable
+// control also focuses it.
+func (c *Container) handleMouse(ev escapebox.Event) bool {
+	if c.captured != nil {
+		return c.handleCapturedMouse(ev)
 	}
 
-	// Scan list before focused control for another Focusable control
-	for i := currentIndex - 1; i >= 0; i-- {
-		f, ok := c.Controls[i].(Focusable)
-		if ok {
+	hits := controlsAt(c.Controls, ev.MouseX, ev.MouseY)
+
+	for i := len(hits) - 1; i >= 0; i-- {
+		// Nested Containers just pass events on to their controls
+		if _, nested := hits[i].control.(*Container); nested {
+			continue
+		}
+
+		f, ok := hits[i].control.(Focusable)
+		if !ok {
+			continue
+		}
+
+		_, isParent := f.(Parent)
+
+		if leftClick(ev) && !isParent && c.root().Focused != f {
 			c.focus(f)
-			return
 		}
+
+		local := ev
+		local.MouseX = hits[i].x
+		local.MouseY = hits[i].y
+
+		if !f.HandleEvent(local) {
+			continue
+		}
+
+		if m, ok := f.(mouseCapturer); ok && m.capturesMouse() {
+			c.captured = m
+		}
+
+		return true
 	}
 
-	// Scan list after focused control (loop around)
-	for i := len(c.Controls) - 1; i >= currentIndex; i-- {
-		f, ok := c.Controls[i].(Focusable)
-		if ok {
-			c.focus(f)
-			return
+	return false
+}
+
+// Send a mouse event to the control which captured the mouse, wherever the
+// pointer is, until it lets go.
+func (c *Container) handleCapturedMouse(ev escapebox.Event) bool {
+	captured := c.captured
+
+	bounds, ok := findBounds(c.Controls, captured)
+	if !ok {
+		c.captured = nil
+		return false
+	}
+
+	local := ev
+	local.MouseX -= bounds.Left
+	local.MouseY -= bounds.Top
+
+	handled := captured.HandleEvent(local)
+
+	if !captured.capturesMouse() {
+		c.captured = nil
+	}
+
+	return handled
+}
+
+// Draw all of a child of a control which contains other controls, like Frame.
+// Such controls draw everything whenever they're drawn, since their area may
+// have been cleared, so nested Containers have to redraw everything too.
+func drawChild(target *DrawTarget, child Control) {
+	childTarget, err := target.Slice(child.GetBounds())
+	if err != nil {
+		target.ReportError(err)
+		return
+	}
+
+	if nested, ok := child.(*Container); ok {
+		nested.Invalidate()
+	}
+
+	child.Draw(childTarget)
+	validate(child)
+}
+
+// Check whether the Container or any of its controls need to be redrawn.
+func (c *Container) Invalid() bool {
+	if c.Invalidation.Invalid() {
+		return true
+	}
+
+	for _, child := range c.Controls {
+		if needsDraw(child) {
+			return true
+		}
+	}
+
+	for _, layer := range c.layers() {
+		if needsDraw(layer) {
+			return true
+		}
+	}
+
+	for _, p := range c.popups {
+		if needsDraw(p) {
+			return true
 		}
 	}
+
+	return false
 }
 
+// Draw the controls which need it, then any open Windows, Dialogs and popups.
+// If the Container itself was invalidated, the whole area is cleared and
+// everything is redrawn.
 func (c *Container) Draw(target *DrawTarget) {
-	for _, child := range c.Controls {
-		childBounds := child.GetBounds()
-		childContext, err := target.Slice(childBounds)
+	redrawAll := c.Invalidation.Invalid()
+
+	if redrawAll {
+		target.Clear()
+	}
+
+	redraw := make([]bool, len(c.Controls))
+	for i, child := range c.Controls {
+		redraw[i] = redrawAll || needsDraw(child)
+	}
+
+	// A redrawn control's area is cleared first, so anything overlapping it
+	// has to be redrawn too. Repeat until no more controls are affected.
+	for changed := true; changed; {
+		changed = false
+
+		for i, child := range c.Controls {
+			if redraw[i] {
+				continue
+			}
+
+			for j, other := range c.Controls {
+				if redraw[j] &&
+					child.GetBounds().Intersects(other.GetBounds()) {
+					redraw[i] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	targets := make([]*DrawTarget, len(c.Controls))
+
+	for i, child := range c.Controls {
+		if !redraw[i] {
+			continue
+		}
+
+		childContext, err := target.Slice(child.GetBounds())
 
 		if err != nil {
-			panic(err)
+			target.ReportError(fmt.Errorf("Can't draw control at %+v: %w",
+				*child.GetBounds(), err))
+			redraw[i] = false
+			continue
+		}
+
+		// Nested Containers clear and redraw as much as they need to,
+		// which is everything if anything else was drawn over them.
+		if nested, ok := child.(*Container); ok {
+			if redrawAll || !needsDraw(child) {
+				nested.Invalidate()
+			}
+		} else if !redrawAll {
+			childContext.Clear()
 		}
 
-		child.Draw(childContext)
+		targets[i] = childContext
 	}
+
+	// The areas drawn over, which Windows on top have to be redrawn over
+	dirty := []Rect{}
+
+	if redrawAll {
+		dirty = append(dirty, Rect{Width: target.Width,
+			Height: target.Height})
+	}
+
+	for i, child := range c.Controls {
+		if redraw[i] {
+			child.Draw(targets[i])
+			validate(child)
+			dirty = append(dirty, *child.GetBounds())
+		}
+	}
+
+	dirty = c.drawWindows(target, dirty)
+
+	redrawn := c.drawDialogs(target, len(dirty) > 0)
+	c.drawPopups(target, redrawn)
+
+	c.validate()
 }
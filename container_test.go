@@ -0,0 +1,89 @@
+package tui_test
+
+import (
+	"github.com/briansteffens/tui"
+	"github.com/briansteffens/tui/tuitest"
+	"github.com/nsf/termbox-go"
+	"testing"
+)
+
+func TestNestedFocus(t *testing.T) {
+	first := &tui.TextBox{Bounds: tui.Rect{Width: 10, Height: 3}}
+	second := &tui.TextBox{Bounds: tui.Rect{Width: 10, Height: 3}}
+	third := &tui.TextBox{Bounds: tui.Rect{Left: 10, Width: 10, Height: 3}}
+	last := &tui.TextBox{Bounds: tui.Rect{Top: 6, Width: 10, Height: 3}}
+
+	panel := &tui.Container{
+		Bounds:   tui.Rect{Top: 3, Width: 20, Height: 3},
+		Controls: []tui.Control{second, third},
+	}
+
+	c := &tui.Container{
+		Controls:            []tui.Control{first, panel, last},
+		KeyBindingFocusNext: tui.KeyBinding{Key: termbox.KeyTab},
+	}
+
+	// Focus starts on second, moves through the panel and back out
+	s := tuitest.Run(t, c, 30, 10, tuitest.Key(termbox.KeyTab),
+		tuitest.Char('x'), tuitest.Key(termbox.KeyTab))
+
+	tuitest.AssertLine(t, s, 4, "           x")
+
+	if c.Focused != last || panel.Focused != nil {
+		t.Errorf("expected focus to leave the panel")
+	}
+}
+
+func TestMouseClick(t *testing.T) {
+	clicked := false
+
+	first := &tui.TextBox{Bounds: tui.Rect{Width: 20, Height: 3},
+		Value: "hello"}
+	second := &tui.TextBox{Bounds: tui.Rect{Top: 3, Width: 20, Height: 3}}
+	button := &tui.Button{
+		Bounds: tui.Rect{Top: 6, Width: 10, Height: 3},
+		Text:   "Go",
+		ClickHandler: func(b *tui.Button) {
+			clicked = true
+		},
+	}
+
+	c := &tui.Container{Controls: []tui.Control{first, second, button}}
+
+	// Clicking the first TextBox focuses it and places the caret, then
+	// clicking the button focuses and presses it
+	tuitest.Run(t, c, 20, 9, tuitest.Click(3, 1), tuitest.Char('X'),
+		tuitest.Click(3, 7))
+
+	if first.Value != "heXllo" || !clicked || c.Focused != button {
+		t.Errorf("unexpected value %q, click %t and focus %T",
+			first.Value, clicked, c.Focused)
+	}
+}
+
+func TestMouseDrag(t *testing.T) {
+	clicks := 0
+
+	check := &tui.CheckBox{Bounds: tui.Rect{Width: 10, Height: 1},
+		Text: "Check"}
+	button := &tui.Button{
+		Bounds: tui.Rect{Top: 1, Width: 10, Height: 3},
+		Text:   "Go",
+		ClickHandler: func(b *tui.Button) {
+			clicks++
+		},
+	}
+
+	c := &tui.Container{Controls: []tui.Control{check, button}}
+
+	// Dragging across a control after pressing on it doesn't press it
+	// again
+	tuitest.Run(t, c, 10, 4, tuitest.Click(1, 0), tuitest.Drag(2, 0),
+		tuitest.Drag(3, 0), tuitest.Click(1, 2), tuitest.Drag(2, 2),
+		tuitest.Drag(3, 2))
+
+	if !check.Checked || clicks != 1 {
+		t.Errorf("expected one toggle and one click, got checked %t "+
+			"and %d clicks", check.Checked, clicks)
+	}
+}
@@ -0,0 +1,735 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/briansteffens/escapebox"
+	"github.com/nsf/termbox-go"
+	"io"
+	"strings"
+)
+
+// A Definition describes a control and, for controls which hold others, the
+// controls inside it. A tree of Definitions can be written as JSON or YAML and
+// loaded with LoadUI or ParseUI.
+//
+// Type picks the control: container, label, textbox, editbox, checkbox,
+// radiobutton, button, detailview, listbox, combobox, frame, scrollview,
+// split or tabview. Fields which don't apply to the type are ignored.
+type Definition struct {
+	Type string `json:"type" yaml:"type"`
+
+	// Used to find the control with UI.Find once it's loaded
+	ID string `json:"id" yaml:"id"`
+
+	// Where the control goes, unless its Container has a layout
+	Bounds Rect `json:"bounds" yaml:"bounds"`
+
+	// The control's size in its Container's vbox or hbox layout, or its
+	// cell in a grid layout
+	Size Size      `json:"size" yaml:"size"`
+	Cell *GridCell `json:"cell" yaml:"cell"`
+
+	// The Label, CheckBox or Button text, the TextBox or ComboBox value or
+	// the EditBox contents
+	Text string `json:"text" yaml:"text"`
+
+	// The Frame title, or the tab title of a Container inside a TabView
+	Title string `json:"title" yaml:"title"`
+
+	// single, double, rounded or none, for TextBoxes, ComboBoxes, Buttons
+	// and Frames
+	Border string `json:"border" yaml:"border"`
+
+	// Whether a CheckBox is checked or a RadioButton selected
+	Checked bool `json:"checked" yaml:"checked"`
+
+	// The name of a RadioButton's group, shared by all the buttons in it,
+	// and the button's value
+	Group string `json:"group" yaml:"group"`
+	Value string `json:"value" yaml:"value"`
+
+	// DetailView contents and "#rrggbb" background overrides
+	Columns    []Column   `json:"columns" yaml:"columns"`
+	Rows       [][]string `json:"rows" yaml:"rows"`
+	RowBg      string     `json:"rowBg" yaml:"rowBg"`
+	RowBgAlt   string     `json:"rowBgAlt" yaml:"rowBgAlt"`
+	SelectedBg string     `json:"selectedBg" yaml:"selectedBg"`
+
+	// ListBox or ComboBox items, whether a ListBox allows selecting
+	// several of them, and whether a ComboBox only allows them
+	Items       []string `json:"items" yaml:"items"`
+	MultiSelect bool     `json:"multiSelect" yaml:"multiSelect"`
+	ListOnly    bool     `json:"listOnly" yaml:"listOnly"`
+
+	// Split settings. Orientation is horizontal or vertical.
+	Orientation string  `json:"orientation" yaml:"orientation"`
+	Ratio       float64 `json:"ratio" yaml:"ratio"`
+	MinFirst    int     `json:"minFirst" yaml:"minFirst"`
+	MinSecond   int     `json:"minSecond" yaml:"minSecond"`
+
+	// Key bindings by name, like "exit": "ctrl+c". See ParseKeyBinding for
+	// how keys are written and keyBindingFields for the names each type
+	// has.
+	KeyBindings map[string]string `json:"keyBindings" yaml:"keyBindings"`
+
+	// How a Container arranges Controls
+	Layout *LayoutDefinition `json:"layout" yaml:"layout"`
+
+	// The controls inside a Container, the Content of a Frame or
+	// ScrollView, the two sides of a Split, or the tabs of a TabView, which
+	// must be Containers
+	Controls []Definition `json:"controls" yaml:"controls"`
+}
+
+// Describes a VBox, HBox or Grid layout. The Size and Cell of each control
+// come from its own Definition.
+type LayoutDefinition struct {
+	// vbox, hbox or grid
+	Type string `json:"type" yaml:"type"`
+
+	Padding Padding `json:"padding" yaml:"padding"`
+
+	// The gap between controls in a vbox or hbox
+	Gap int `json:"gap" yaml:"gap"`
+
+	// Grid settings
+	Columns   []Size `json:"columns" yaml:"columns"`
+	Rows      []Size `json:"rows" yaml:"rows"`
+	ColumnGap int    `json:"columnGap" yaml:"columnGap"`
+	RowGap    int    `json:"rowGap" yaml:"rowGap"`
+}
+
+// A screen loaded from a Definition.
+type UI struct {
+	// Give this to MainLoop
+	Root *Container
+
+	controls map[string]Control
+	groups   map[string]*RadioGroup
+}
+
+// Load a UI from a JSON Definition. Unknown fields are an error, to catch
+// typos.
+func LoadUI(r io.Reader) (*UI, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	var def Definition
+	if err := decoder.Decode(&def); err != nil {
+		return nil, fmt.Errorf("Can't parse UI definition: %w", err)
+	}
+
+	return NewUI(def)
+}
+
+// Load a UI from a Definition in any format unmarshal understands. For YAML,
+// pass the Unmarshal function of a YAML package like gopkg.in/yaml.v3.
+//
+// Unlike LoadUI, unknown fields are only an error if unmarshal makes them
+// one; most Unmarshal functions silently ignore them.
+func ParseUI(data []byte,
+	unmarshal func(data []byte, v interface{}) error) (*UI, error) {
+	var def Definition
+	if err := unmarshal(data, &def); err != nil {
+		return nil, fmt.Errorf("Can't parse UI definition: %w", err)
+	}
+
+	return NewUI(def)
+}
+
+// Create the controls described by def. The root Definition has to be a
+// container; its Type may be left out.
+func NewUI(def Definition) (*UI, error) {
+	if def.Type == "" {
+		def.Type = "container"
+	}
+
+	if def.Type != "container" {
+		return nil, fmt.Errorf("The root control must be a container, "+
+			"not %q", def.Type)
+	}
+
+	ui := &UI{
+		controls: map[string]Control{},
+		groups:   map[string]*RadioGroup{},
+	}
+
+	root, err := ui.build(def, "root")
+	if err != nil {
+		return nil, err
+	}
+
+	ui.Root = root.(*Container)
+
+	return ui, nil
+}
+
+// The control with the given ID, or nil if there isn't one.
+func (ui *UI) Find(id string) Control {
+	return ui.controls[id]
+}
+
+// The Container with the given ID, or nil if there isn't one.
+func (ui *UI) Container(id string) *Container {
+	c, _ := ui.controls[id].(*Container)
+	return c
+}
+
+// The Label with the given ID, or nil if there isn't one.
+func (ui *UI) Label(id string) *Label {
+	l, _ := ui.controls[id].(*Label)
+	return l
+}
+
+// The TextBox with the given ID, or nil if there isn't one.
+func (ui *UI) TextBox(id string) *TextBox {
+	t, _ := ui.controls[id].(*TextBox)
+	return t
+}
+
+// The EditBox with the given ID, or nil if there isn't one.
+func (ui *UI) EditBox(id string) *EditBox {
+	e, _ := ui.controls[id].(*EditBox)
+	return e
+}
+
+// The CheckBox with the given ID, or nil if there isn't one.
+func (ui *UI) CheckBox(id string) *CheckBox {
+	c, _ := ui.controls[id].(*CheckBox)
+	return c
+}
+
+// The Button with the given ID, or nil if there isn't one.
+func (ui *UI) Button(id string) *Button {
+	b, _ := ui.controls[id].(*Button)
+	return b
+}
+
+// The DetailView with the given ID, or nil if there isn't one.
+func (ui *UI) DetailView(id string) *DetailView {
+	d, _ := ui.controls[id].(*DetailView)
+	return d
+}
+
+// The ListBox with the given ID, or nil if there isn't one.
+func (ui *UI) ListBox(id string) *ListBox {
+	l, _ := ui.controls[id].(*ListBox)
+	return l
+}
+
+// The ComboBox with the given ID, or nil if there isn't one.
+func (ui *UI) ComboBox(id string) *ComboBox {
+	c, _ := ui.controls[id].(*ComboBox)
+	return c
+}
+
+// The RadioGroup with the given name, or nil if there isn't one.
+func (ui *UI) RadioGroup(name string) *RadioGroup {
+	return ui.groups[name]
+}
+
+// Create the control for def and everything inside it. path says where def
+// is, for error messages.
+func (ui *UI) build(def Definition, path string) (Control, error) {
+	if def.ID != "" {
+		path = fmt.Sprintf("%s (%s)", path, def.ID)
+	}
+
+	ctrl, err := ui.buildControl(def, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bindKeys(ctrl, def.KeyBindings); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if def.ID == "" {
+		return ctrl, nil
+	}
+
+	if _, ok := ui.controls[def.ID]; ok {
+		return nil, fmt.Errorf("%s: Duplicate ID %q", path, def.ID)
+	}
+
+	ui.controls[def.ID] = ctrl
+
+	return ctrl, nil
+}
+
+func (ui *UI) buildControl(def Definition, path string) (Control, error) {
+	border, err := parseBorder(def.Border)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	switch def.Type {
+	case "container":
+		return ui.buildContainer(def, path)
+	case "label":
+		return &Label{Bounds: def.Bounds, Text: def.Text}, nil
+	case "textbox":
+		return &TextBox{Bounds: def.Bounds, Value: def.Text,
+			Border: border}, nil
+	case "editbox":
+		e := &EditBox{Bounds: def.Bounds}
+		e.SetText(def.Text)
+		return e, nil
+	case "checkbox":
+		return &CheckBox{Bounds: def.Bounds, Text: def.Text,
+			Checked: def.Checked}, nil
+	case "radiobutton":
+		return ui.buildRadioButton(def), nil
+	case "button":
+		return &Button{Bounds: def.Bounds, Text: def.Text,
+			Border: border}, nil
+	case "detailview":
+		return buildDetailView(def, path)
+	case "listbox":
+		l := &ListBox{Bounds: def.Bounds, Items: StringList(def.Items)}
+		if def.MultiSelect {
+			l.Mode = SelectMulti
+		}
+		return l, nil
+	case "combobox":
+		return &ComboBox{
+			TextBox: TextBox{Bounds: def.Bounds, Value: def.Text,
+				Border: border},
+			Items:    def.Items,
+			ListOnly: def.ListOnly,
+		}, nil
+	}
+
+	children, err := ui.buildChildren(def, path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch def.Type {
+	case "frame", "scrollview":
+		if len(children) > 1 {
+			return nil, fmt.Errorf("%s: A %s holds one control, not %d",
+				path, def.Type, len(children))
+		}
+
+		var content Control
+		if len(children) == 1 {
+			content = children[0]
+		}
+
+		if def.Type == "scrollview" {
+			return &ScrollView{Bounds: def.Bounds, Content: content},
+				nil
+		}
+
+		return &Frame{Bounds: def.Bounds, Border: border,
+			Title: def.Title, Content: content}, nil
+	case "split":
+		return buildSplit(def, children, path)
+	case "tabview":
+		t := &TabView{Bounds: def.Bounds}
+
+		for i, child := range children {
+			content, ok := child.(*Container)
+			if !ok {
+				return nil, fmt.Errorf("%s.controls[%d]: Tabs "+
+					"must be containers", path, i)
+			}
+
+			t.Tabs = append(t.Tabs, &Tab{
+				Title:   def.Controls[i].Title,
+				Content: content,
+			})
+		}
+
+		return t, nil
+	}
+
+	return nil, fmt.Errorf("%s: Unknown control type %q", path, def.Type)
+}
+
+func (ui *UI) buildChildren(def Definition, path string) ([]Control, error) {
+	ret := []Control{}
+
+	for i, childDef := range def.Controls {
+		child, err := ui.build(childDef,
+			fmt.Sprintf("%s.controls[%d]", path, i))
+		if err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, child)
+	}
+
+	return ret, nil
+}
+
+func (ui *UI) buildContainer(def Definition, path string) (Control, error) {
+	children, err := ui.buildChildren(def, path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Container{Bounds: def.Bounds, Controls: children}
+
+	if def.Layout == nil {
+		return c, nil
+	}
+
+	c.Layout, err = buildLayout(*def.Layout, def.Controls)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return c, nil
+}
+
+// Create a Layout, taking the sizes or cells of the controls from their
+// Definitions.
+func buildLayout(def LayoutDefinition, controls []Definition) (Layout,
+	error) {
+	sizes := []Size{}
+	for _, control := range controls {
+		sizes = append(sizes, control.Size)
+	}
+
+	switch def.Type {
+	case "vbox":
+		return &VBox{Sizes: sizes, Padding: def.Padding, Gap: def.Gap}, nil
+	case "hbox":
+		return &HBox{Sizes: sizes, Padding: def.Padding, Gap: def.Gap}, nil
+	case "grid":
+		grid := &Grid{
+			Columns:   def.Columns,
+			Rows:      def.Rows,
+			Padding:   def.Padding,
+			ColumnGap: def.ColumnGap,
+			RowGap:    def.RowGap,
+		}
+
+		// Controls without a cell go where the Grid would put them
+		// anyway
+		columns := max(1, len(def.Columns))
+		rows := max(1, len(def.Rows))
+
+		for i, control := range controls {
+			cell := GridCell{Column: i % columns, Row: i / columns}
+
+			if control.Cell != nil {
+				cell = *control.Cell
+
+				if err := checkCell(cell, columns, rows); err != nil {
+					return nil, fmt.Errorf("controls[%d]: %w", i,
+						err)
+				}
+			}
+
+			grid.Cells = append(grid.Cells, cell)
+		}
+
+		return grid, nil
+	}
+
+	return nil, fmt.Errorf("Unknown layout type %q", def.Type)
+}
+
+// Check that a cell from a Definition is inside a grid of the given size.
+func checkCell(cell GridCell, columns, rows int) error {
+	if cell.ColumnSpan < 0 || cell.RowSpan < 0 {
+		return errors.New("Cell spans can't be negative")
+	}
+
+	if cell.Column < 0 || cell.Row < 0 || cell.Column >= columns ||
+		cell.Row >= rows {
+		return fmt.Errorf("Cell at column %d, row %d is outside the "+
+			"%dx%d grid", cell.Column, cell.Row, columns, rows)
+	}
+
+	return nil
+}
+
+// Create a RadioButton, adding it to its group.
+func (ui *UI) buildRadioButton(def Definition) Control {
+	b := &RadioButton{Bounds: def.Bounds, Text: def.Text,
+		Value: def.Value}
+
+	if def.Group == "" {
+		return b
+	}
+
+	g, ok := ui.groups[def.Group]
+	if !ok {
+		g = &RadioGroup{}
+		ui.groups[def.Group] = g
+	}
+
+	g.Add(b)
+
+	if def.Checked {
+		b.Select()
+	}
+
+	return b
+}
+
+func buildDetailView(def Definition, path string) (Control, error) {
+	d := &DetailView{Bounds: def.Bounds, Columns: def.Columns,
+		Rows: def.Rows}
+
+	colors := []struct {
+		hex   string
+		color *Color
+	}{
+		{def.RowBg, &d.RowBg},
+		{def.RowBgAlt, &d.RowBgAlt},
+		{def.SelectedBg, &d.SelectedBg},
+	}
+
+	for _, c := range colors {
+		if c.hex == "" {
+			continue
+		}
+
+		color, err := HexColor(c.hex)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		*c.color = color
+	}
+
+	return d, nil
+}
+
+func buildSplit(def Definition, children []Control, path string) (Control,
+	error) {
+	if len(children) != 2 {
+		return nil, fmt.Errorf("%s: A split holds two controls, not %d",
+			path, len(children))
+	}
+
+	s := &Split{
+		Bounds:    def.Bounds,
+		First:     children[0],
+		Second:    children[1],
+		Ratio:     def.Ratio,
+		MinFirst:  def.MinFirst,
+		MinSecond: def.MinSecond,
+	}
+
+	switch def.Orientation {
+	case "", "horizontal":
+	case "vertical":
+		s.Orientation = Vertical
+	default:
+		return nil, fmt.Errorf("%s: Unknown orientation %q", path,
+			def.Orientation)
+	}
+
+	return s, nil
+}
+
+func parseBorder(name string) (BorderStyle, error) {
+	switch name {
+	case "", "none":
+		return BorderNone, nil
+	case "single":
+		return BorderSingle, nil
+	case "double":
+		return BorderDouble, nil
+	case "rounded":
+		return BorderRounded, nil
+	}
+
+	return BorderNone, fmt.Errorf("Unknown border %q", name)
+}
+
+// The key bindings of a control which can be set in a Definition, by name.
+func keyBindingFields(ctrl Control) map[string]*KeyBinding {
+	switch ctrl := ctrl.(type) {
+	case *Container:
+		return map[string]*KeyBinding{
+			"exit":          &ctrl.KeyBindingExit,
+			"focusNext":     &ctrl.KeyBindingFocusNext,
+			"focusPrevious": &ctrl.KeyBindingFocusPrevious,
+			"focusUp":       &ctrl.KeyBindingFocusUp,
+			"focusDown":     &ctrl.KeyBindingFocusDown,
+			"focusLeft":     &ctrl.KeyBindingFocusLeft,
+			"focusRight":    &ctrl.KeyBindingFocusRight,
+			"windowNext":    &ctrl.KeyBindingWindowNext,
+			"windowClose":   &ctrl.KeyBindingWindowClose,
+			"windowMove":    &ctrl.KeyBindingWindowMove,
+			"windowResize":  &ctrl.KeyBindingWindowResize,
+		}
+	case *Split:
+		return map[string]*KeyBinding{
+			"grow":     &ctrl.KeyBindingGrow,
+			"shrink":   &ctrl.KeyBindingShrink,
+			"collapse": &ctrl.KeyBindingCollapse,
+		}
+	case *TabView:
+		return map[string]*KeyBinding{
+			"next":     &ctrl.KeyBindingNext,
+			"previous": &ctrl.KeyBindingPrevious,
+		}
+	}
+
+	return map[string]*KeyBinding{}
+}
+
+func bindKeys(ctrl Control, bindings map[string]string) error {
+	fields := keyBindingFields(ctrl)
+
+	for name, key := range bindings {
+		field, ok := fields[name]
+		if !ok {
+			return fmt.Errorf("Unknown key binding %q", name)
+		}
+
+		kb, err := ParseKeyBinding(key)
+		if err != nil {
+			return err
+		}
+
+		*field = kb
+	}
+
+	return nil
+}
+
+// Keys which can be bound by name
+var namedKeys = map[string]termbox.Key{
+	"tab":       termbox.KeyTab,
+	"enter":     termbox.KeyEnter,
+	"esc":       termbox.KeyEsc,
+	"space":     termbox.KeySpace,
+	"backspace": termbox.KeyBackspace2,
+	"delete":    termbox.KeyDelete,
+	"insert":    termbox.KeyInsert,
+	"home":      termbox.KeyHome,
+	"end":       termbox.KeyEnd,
+	"pgup":      termbox.KeyPgup,
+	"pgdn":      termbox.KeyPgdn,
+	"up":        termbox.KeyArrowUp,
+	"down":      termbox.KeyArrowDown,
+	"left":      termbox.KeyArrowLeft,
+	"right":     termbox.KeyArrowRight,
+}
+
+// Key combinations which arrive as escape sequences
+var namedSequences = map[string]int{
+	"shift+tab": SeqShiftTab,
+	"alt+up":    SeqAltUp,
+	"alt+down":  SeqAltDown,
+	"alt+left":  SeqAltLeft,
+	"alt+right": SeqAltRight,
+	"ctrl+pgup": SeqCtrlPageUp,
+	"ctrl+pgdn": SeqCtrlPageDown,
+}
+
+// Parse a key written like "q", "enter", "f5", "ctrl+c", "shift+tab",
+// "alt+1" or "alt+f". Names are case-insensitive, single characters aren't.
+func ParseKeyBinding(key string) (KeyBinding, error) {
+	if runes := []rune(key); len(runes) == 1 {
+		return KeyBinding{Ch: runes[0]}, nil
+	}
+
+	name := strings.ToLower(key)
+
+	if k, ok := namedKeys[name]; ok {
+		return KeyBinding{Key: k}, nil
+	}
+
+	if seq, ok := namedSequences[name]; ok {
+		return KeyBinding{Seq: escapebox.Sequence(seq)}, nil
+	}
+
+	var n int
+	var letter rune
+
+	if _, err := fmt.Sscanf(name, "f%d", &n); err == nil &&
+		n >= 1 && n <= 12 && name == fmt.Sprintf("f%d", n) {
+		return KeyBinding{Key: termbox.KeyF1 - termbox.Key(n-1)}, nil
+	}
+
+	if _, err := fmt.Sscanf(name, "alt+%d", &n); err == nil &&
+		n >= 1 && n <= 9 && len(name) == 5 {
+		return KeyBinding{Seq: escapebox.Sequence(SeqAlt1 + n - 1)}, nil
+	}
+
+	if _, err := fmt.Sscanf(name, "alt+%c", &letter); err == nil &&
+		letter >= 'a' && letter <= 'z' && len(name) == 5 {
+		return KeyBinding{Seq: escapebox.Sequence(SeqAltA +
+			int(letter-'a'))}, nil
+	}
+
+	if _, err := fmt.Sscanf(name, "ctrl+%c", &letter); err == nil &&
+		letter >= 'a' && letter <= 'z' && len(name) == 6 {
+		return KeyBinding{Key: termbox.KeyCtrlA +
+			termbox.Key(letter-'a')}, nil
+	}
+
+	return KeyBinding{}, fmt.Errorf("Unknown key %q", key)
+}
+
+// Describe the key for people, like "Ctrl+S", "F5" or "Shift+Tab", as shown
+// next to menu items. Returns "" for a KeyBinding which matches nothing.
+func (kb KeyBinding) String() string {
+	if kb.Seq != 0 {
+		seq := int(kb.Seq)
+
+		for name, s := range namedSequences {
+			if s == seq {
+				return keyTitle(name)
+			}
+		}
+
+		switch {
+		case seq >= SeqAlt1 && seq <= SeqAlt9:
+			return fmt.Sprintf("Alt+%d", seq-SeqAlt1+1)
+		case seq >= SeqAltA && seq <= SeqAltZ:
+			return fmt.Sprintf("Alt+%c", 'A'+seq-SeqAltA)
+		}
+
+		return ""
+	}
+
+	if kb.Ch != 0 {
+		return string(kb.Ch)
+	}
+
+	for name, k := range namedKeys {
+		if k == kb.Key && kb.Key != 0 {
+			return keyTitle(name)
+		}
+	}
+
+	switch {
+	case kb.Key <= termbox.KeyF1 && kb.Key >= termbox.KeyF12:
+		return fmt.Sprintf("F%d", termbox.KeyF1-kb.Key+1)
+	case kb.Key >= termbox.KeyCtrlA && kb.Key <= termbox.KeyCtrlZ:
+		return fmt.Sprintf("Ctrl+%c", 'A'+kb.Key-termbox.KeyCtrlA)
+	}
+
+	return ""
+}
+
+// Capitalize the parts of a key name, like "shift+tab" to "Shift+Tab".
+func keyTitle(name string) string {
+	switch name {
+	case "pgup", "pgdn":
+		return "Pg" + strings.ToUpper(name[2:3]) + name[3:]
+	case "ctrl+pgup", "ctrl+pgdn":
+		return "Ctrl+" + keyTitle(name[5:])
+	}
+
+	parts := strings.Split(name, "+")
+
+	for i, part := range parts {
+		parts[i] = strings.ToUpper(part[:1]) + part[1:]
+	}
+
+	return strings.Join(parts, "+")
+}
@@ -0,0 +1,150 @@
+package tui_test
+
+import (
+	"encoding/json"
+	"github.com/briansteffens/tui"
+	"github.com/briansteffens/tui/tuitest"
+	"github.com/nsf/termbox-go"
+	"strings"
+	"testing"
+)
+
+func TestLoadUI(t *testing.T) {
+	ui, err := tui.LoadUI(strings.NewReader(`{
+		"keyBindings": {"focusNext": "tab", "exit": "ctrl+c"},
+		"layout": {"type": "vbox", "gap": 1},
+		"controls": [
+			{"type": "label", "text": "Name:", "size": {"fixed": 1}},
+			{"type": "textbox", "id": "name", "border": "single",
+			 "size": {"fixed": 3}},
+			{"type": "button", "id": "save", "text": "Save",
+			 "size": {"fixed": 3}},
+			{"type": "detailview", "id": "rows",
+			 "columns": [{"name": "ID", "width": 4}],
+			 "rows": [["1"], ["2"]]}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	saved := ""
+	ui.Button("save").ClickHandler = func(b *tui.Button) {
+		saved = ui.TextBox("name").Value
+	}
+
+	// Focus starts on the TextBox
+	events := append(tuitest.Type("bob"), tuitest.Click(0, 6))
+
+	s := tuitest.Run(t, ui.Root, 20, 14, events...)
+
+	tuitest.AssertLine(t, s, 0, "Name:")
+	tuitest.AssertLine(t, s, 3, "│bob               │")
+
+	if saved != "bob" {
+		t.Errorf("expected bob to be saved, got %q", saved)
+	}
+
+	if ui.DetailView("rows") == nil || ui.Find("missing") != nil ||
+		ui.Label("save") != nil {
+		t.Error("controls found by the wrong ID or type")
+	}
+
+	_, err = tui.LoadUI(strings.NewReader(
+		`{"controls": [{"type": "split", "controls": []}]}`))
+	if err == nil || err.Error() !=
+		"root.controls[0]: A split holds two controls, not 0" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestLoadUIGridCells(t *testing.T) {
+	_, err := tui.LoadUI(strings.NewReader(`{
+		"layout": {"type": "grid", "columns": [{}, {}]},
+		"controls": [
+			{"type": "label", "cell": {"column": 1}},
+			{"type": "label", "cell": {"column": -1}}
+		]
+	}`))
+
+	if err == nil || err.Error() != "root: controls[1]: Cell at column "+
+		"-1, row 0 is outside the 2x1 grid" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestParseUI(t *testing.T) {
+	// Any Unmarshal function will do, and the json and yaml tags match
+	data := []byte(`{
+		"keyBindings": {"exit": "ctrl+q"},
+		"layout": {"type": "vbox", "padding": {"left": 1, "top": 1}},
+		"controls": [
+			{"type": "label", "id": "title", "text": "Settings",
+			 "size": {"fixed": 1}, "colour": "ignored"},
+			{"type": "label", "id": "hidden", "text": "Hidden",
+			 "size": {"fixed": 0}},
+			{"type": "checkbox", "id": "wrap", "text": "Wrap",
+			 "checked": true, "size": {"fixed": 1}},
+			{"type": "radiobutton", "group": "mode", "value": "fast",
+			 "text": "Fast", "size": {"fixed": 1}},
+			{"type": "radiobutton", "group": "mode", "value": "safe",
+			 "text": "Safe", "checked": true, "size": {"fixed": 1}},
+			{"type": "listbox", "id": "files", "items": ["a", "b"],
+			 "multiSelect": true, "size": {"fixed": 2}},
+			{"type": "combobox", "id": "lang", "text": "go",
+			 "items": ["go", "c"], "listOnly": true,
+			 "border": "single", "size": {"fixed": 3}},
+			{"type": "frame", "id": "notes", "title": "Notes",
+			 "controls": [{"type": "editbox", "text": "hi"}]}
+		]
+	}`)
+
+	ui, err := tui.ParseUI(data, json.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := tuitest.Run(t, ui.Root, 20, 16)
+
+	tuitest.AssertLine(t, s, 1, " Settings")
+	tuitest.AssertLine(t, s, 2, " [X] Wrap")
+
+	if *ui.Label("hidden").GetBounds() != (tui.Rect{Left: 1, Top: 2,
+		Width: 19}) {
+		t.Errorf("expected the hidden label to have no height, got %+v",
+			*ui.Label("hidden").GetBounds())
+	}
+
+	if ui.RadioGroup("mode").Selected() != "safe" {
+		t.Errorf("expected safe to be selected, got %q",
+			ui.RadioGroup("mode").Selected())
+	}
+
+	files := ui.ListBox("files")
+	if files.Mode != tui.SelectMulti || files.Items.Len() != 2 {
+		t.Errorf("unexpected ListBox mode %d with %d items", files.Mode,
+			files.Items.Len())
+	}
+
+	lang := ui.ComboBox("lang")
+	if lang.Value != "go" || !lang.ListOnly ||
+		lang.Border != tui.BorderSingle || len(lang.Items) != 2 {
+		t.Errorf("unexpected ComboBox %+v", lang)
+	}
+
+	notes, ok := ui.Find("notes").(*tui.Frame)
+	if !ok || notes.Title != "Notes" || notes.Content == nil {
+		t.Errorf("unexpected Frame %+v", ui.Find("notes"))
+	}
+
+	if ui.Root.KeyBindingExit != (tui.KeyBinding{Key: termbox.KeyCtrlQ}) {
+		t.Errorf("unexpected exit key binding %+v",
+			ui.Root.KeyBindingExit)
+	}
+
+	_, err = tui.ParseUI([]byte(`{"controls": [`), json.Unmarshal)
+	if err == nil || !strings.HasPrefix(err.Error(),
+		"Can't parse UI definition: ") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
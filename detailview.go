@@ -11,17 +11,22 @@ type Column struct {
 }
 
 type DetailView struct {
-	Bounds     Rect
-	focus      bool
-	scrollCol  int
-	scrollRow  int
-	cursorCol  int
-	cursorRow  int
-	Columns    []Column
-	Rows       [][]string
-	RowBg      termbox.Attribute
-	RowBgAlt   termbox.Attribute
-	SelectedBg termbox.Attribute
+	Invalidation
+
+	Bounds    Rect
+	focus     bool
+	scrollCol int
+	scrollRow int
+	cursorCol int
+	cursorRow int
+	Columns   []Column
+	Rows      [][]string
+
+	// Background overrides. When left at zero, the backgrounds of the
+	// theme's Row, AltRow and Selected styles are used.
+	RowBg      Color
+	RowBgAlt   Color
+	SelectedBg Color
 }
 
 func (d *DetailView) GetBounds() *Rect {
@@ -35,6 +40,7 @@ func (d *DetailView) Reset() {
 	d.cursorRow = 0
 	d.Columns = []Column{}
 	d.Rows = [][]string{}
+	d.Invalidate()
 }
 
 func (d *DetailView) SetCursor(row, col int) {
@@ -46,10 +52,12 @@ func (d *DetailView) SetCursor(row, col int) {
 
 func (d *DetailView) SetFocus() {
 	d.focus = true
+	d.Invalidate()
 }
 
 func (d *DetailView) UnsetFocus() {
 	d.focus = false
+	d.Invalidate()
 }
 
 func renderValue(src string, maxWidth int) string {
@@ -165,8 +173,8 @@ func (d *DetailView) Draw(target *DrawTarget) {
 
 		maxLen = min(maxLen, d.viewWidth())
 
-		target.Print(left, top, termbox.ColorWhite|termbox.AttrBold,
-			termbox.ColorBlack, renderValue(name, maxLen))
+		target.Print(left, top, theme.Header.Fg, theme.Header.Bg,
+			renderValue(name, maxLen))
 
 		left += col.Width
 
@@ -179,18 +187,28 @@ func (d *DetailView) Draw(target *DrawTarget) {
 		left = 0
 		top++
 
-		rowColor := d.RowBg
+		rowStyle := theme.Row
+		if d.RowBg != 0 {
+			rowStyle.Bg = d.RowBg
+		}
+
 		if r%2 == 0 {
-			rowColor = d.RowBgAlt
+			rowStyle = theme.AltRow
+			if d.RowBgAlt != 0 {
+				rowStyle.Bg = d.RowBgAlt
+			}
 		}
 
 		for ci := firstCol; ci <= lastCol; ci++ {
 			col := d.Columns[ci]
 
-			colColor := rowColor
+			style := rowStyle
 
 			if d.cursorCol == ci && d.cursorRow == r && d.focus {
-				colColor = d.SelectedBg
+				style = theme.Selected
+				if d.SelectedBg != 0 {
+					style.Bg = d.SelectedBg
+				}
 			}
 
 			val := d.Rows[r][ci]
@@ -223,8 +241,7 @@ func (d *DetailView) Draw(target *DrawTarget) {
 				val = val + " "
 			}
 
-			target.Print(left, top, termbox.ColorWhite, colColor,
-				val)
+			target.Print(left, top, style.Fg, style.Bg, val)
 
 			left += col.Width
 
@@ -235,11 +252,45 @@ func (d *DetailView) Draw(target *DrawTarget) {
 	}
 
 	if d.focus {
-		termbox.HideCursor()
+		target.HideCursor()
 	}
 }
 
+func (d *DetailView) handleMouseEvent(ev escapebox.Event) bool {
+	if direction := wheelDirection(ev); direction != 0 {
+		d.scrollRow += direction * wheelLines
+		d.cursorRow += direction * wheelLines
+		d.updateScroll()
+		return true
+	}
+
+	// Clicks on the header row don't select anything
+	if !leftClick(ev) || ev.MouseY == 0 {
+		return false
+	}
+
+	row := d.scrollRow + ev.MouseY - 1
+	if row >= len(d.Rows) {
+		return false
+	}
+
+	x := d.scrollCol + ev.MouseX
+
+	for ci := range d.Columns {
+		if x >= d.columnLeft(ci) && x <= d.columnRight(ci) {
+			d.SetCursor(row, ci)
+			return true
+		}
+	}
+
+	return false
+}
+
 func (d *DetailView) HandleEvent(ev escapebox.Event) bool {
+	if ev.Type == termbox.EventMouse {
+		return d.handleMouseEvent(ev)
+	}
+
 	if ev.Type != termbox.EventKey {
 		return false
 	}
@@ -271,6 +322,7 @@ func (d *DetailView) HandleEvent(ev escapebox.Event) bool {
 		if d.Columns[d.cursorCol].Width > 1 {
 			d.Columns[d.cursorCol].Width--
 		}
+		handled = true
 	}
 
 	switch ev.Key {
@@ -305,10 +357,16 @@ func (d *DetailView) HandleEvent(ev escapebox.Event) bool {
 		d.updateScroll()
 	}
 
+	if handled {
+		d.Invalidate()
+	}
+
 	return handled
 }
 
 func (d *DetailView) updateScroll() {
+	d.Invalidate()
+
 	// Clamp cursor
 	d.cursorRow = max(0, d.cursorRow)
 	d.cursorRow = min(len(d.Rows)-1, d.cursorRow)
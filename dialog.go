@@ -0,0 +1,325 @@
+package tui
+
+import (
+	"github.com/briansteffens/escapebox"
+	"github.com/nsf/termbox-go"
+	"strings"
+)
+
+// A Dialog is a Container shown on top of the root Container with ShowDialog.
+// It's centered on the screen with a border and a shadow, and while it's open
+// all input goes to it. Bounds sets the size of the area inside the border;
+// its position is worked out automatically.
+type Dialog struct {
+	Container
+
+	// Shown in the top border
+	Title string
+
+	// What had focus before the Dialog was shown
+	previousFocus Focusable
+}
+
+// Show d on top of everything else and move focus into it. Dialogs stack: the
+// last one shown gets the input until it's closed.
+func (c *Container) ShowDialog(d *Dialog) {
+	root := c.root()
+
+	d.previousFocus = root.Focused
+	d.parent = root
+	root.dialogs = append(root.dialogs, d)
+
+	d.center(root.Bounds)
+	d.Arrange()
+
+	root.focus(nil)
+	d.SetFocus()
+}
+
+// Remove the Dialog from the screen. If it was the topmost one, focus goes
+// back to whatever had it before the Dialog was shown.
+func (d *Dialog) Close() {
+	root := d.root()
+
+	for i, open := range root.dialogs {
+		if open != d {
+			continue
+		}
+
+		top := i == len(root.dialogs)-1
+		root.dialogs = append(root.dialogs[:i], root.dialogs[i+1:]...)
+
+		if top {
+			root.focus(d.previousFocus)
+		} else {
+			// Whatever was shown above d came back to d's focus
+			root.dialogs[i].previousFocus = d.previousFocus
+		}
+
+		break
+	}
+
+	d.parent = nil
+
+	// Uncover what was underneath
+	root.Invalidate()
+}
+
+// The Dialog which currently gets all input, if any.
+func (c *Container) topDialog() *Dialog {
+	root := c.root()
+
+	if len(root.dialogs) == 0 {
+		return nil
+	}
+
+	return root.dialogs[len(root.dialogs)-1]
+}
+
+// The Container focus bindings apply to: the top Dialog if there is one,
+// then the focused Window, otherwise the root.
+func (c *Container) active() *Container {
+	if d := c.topDialog(); d != nil {
+		return &d.Container
+	}
+
+	if w := c.focusedWindow(); w != nil {
+		return &w.Container
+	}
+
+	return c.root()
+}
+
+// Center the Dialog, including its border, inside area.
+func (d *Dialog) center(area Rect) {
+	d.Bounds.Left = max(1, (area.Width-d.Bounds.Width-2)/2+1)
+	d.Bounds.Top = max(1, (area.Height-d.Bounds.Height-2)/2+1)
+}
+
+// The Dialog's area including its border and shadow.
+func (d *Dialog) frame() *Rect {
+	return &Rect{
+		Left:   d.Bounds.Left - 1,
+		Top:    d.Bounds.Top - 1,
+		Width:  d.Bounds.Width + 3,
+		Height: d.Bounds.Height + 3,
+	}
+}
+
+// Draw the border, title and shadow around the Dialog.
+func (d *Dialog) drawFrame(target *DrawTarget) {
+	width := d.Bounds.Width + 2
+	height := d.Bounds.Height + 2
+
+	border, err := target.Slice(&Rect{Width: width, Height: height})
+	if err == nil {
+		border.Border(BorderSingle, theme.Border, d.Title)
+	}
+
+	shadow := theme.Shadow
+
+	for y := 1; y <= height; y++ {
+		target.SetCell(width, y, shadow.Fg, shadow.Bg, ' ')
+	}
+
+	for x := 1; x < width; x++ {
+		target.SetCell(x, height, shadow.Fg, shadow.Bg, ' ')
+	}
+}
+
+// Draw the open Dialogs on top of the Container's controls. If anything
+// underneath was redrawn, every Dialog is too. Returns whether anything was
+// drawn, including underneath.
+func (c *Container) drawDialogs(target *DrawTarget, redrawAll bool) bool {
+	drawn := redrawAll
+
+	for _, d := range c.dialogs {
+		if redrawAll || d.Invalidation.Invalid() {
+			frame, err := target.Slice(d.frame())
+			if err != nil {
+				target.ReportError(err)
+				continue
+			}
+
+			d.drawFrame(frame)
+			d.Invalidate()
+
+			// Anything above overlaps this one
+			redrawAll = true
+		}
+
+		if !d.Invalid() {
+			continue
+		}
+
+		inner, err := target.Slice(&d.Bounds)
+		if err != nil {
+			target.ReportError(err)
+			continue
+		}
+
+		d.Container.Draw(inner)
+		drawn = true
+	}
+
+	return drawn
+}
+
+// Dispatch a mouse event to the top Dialog. Clicks outside it are swallowed.
+func (d *Dialog) handleMouse(ev escapebox.Event) bool {
+	if d.Bounds.ContainsPoint(ev.MouseX, ev.MouseY) {
+		local := ev
+		local.MouseX -= d.Bounds.Left
+		local.MouseY -= d.Bounds.Top
+
+		d.Container.handleMouse(local)
+	}
+
+	return true
+}
+
+// Minimum width of the ready-made dialogs
+const minDialogWidth = 24
+
+// Lay out the ready-made dialogs: lines of text, optionally a TextBox, and a
+// row of buttons centered along the bottom.
+func newDialog(title, message string, input *TextBox,
+	buttons ...*Button) *Dialog {
+	d := &Dialog{Title: title}
+
+	lines := strings.Split(message, "\n")
+
+	buttonsWidth := 0
+	for _, b := range buttons {
+		buttonsWidth += len(b.Text) + 4
+	}
+
+	width := max(minDialogWidth, buttonsWidth+2)
+	for _, line := range lines {
+		width = max(width, len(line)+4)
+	}
+
+	top := 1
+
+	for _, line := range lines {
+		d.Controls = append(d.Controls, &Label{
+			Bounds: Rect{Left: 2, Top: top, Width: len(line),
+				Height: 1},
+			Text: line,
+		})
+		top++
+	}
+
+	if input != nil {
+		input.Bounds = Rect{Left: 1, Top: top, Width: width - 2,
+			Height: 3}
+		d.Controls = append(d.Controls, input)
+		top += 3
+	}
+
+	left := (width - buttonsWidth) / 2
+
+	for _, b := range buttons {
+		b.Bounds = Rect{Left: left, Top: top, Width: len(b.Text) + 4,
+			Height: 3}
+		d.Controls = append(d.Controls, b)
+		left += b.Bounds.Width
+	}
+
+	d.Bounds = Rect{Width: width, Height: top + 3}
+
+	return d
+}
+
+// Create a Dialog which closes, and then calls done if it isn't nil, when a
+// button is clicked or Enter or Esc is pressed. Enter picks the focused
+// button, or the first one if a TextBox has focus; Esc picks the last.
+func newChoiceDialog(title, message string, input *TextBox,
+	choices []string, done func(choice int)) *Dialog {
+	buttons := make([]*Button, len(choices))
+
+	var d *Dialog
+
+	choose := func(choice int) {
+		d.Close()
+
+		if done != nil {
+			done(choice)
+		}
+	}
+
+	for i, text := range choices {
+		choice := i
+		buttons[i] = &Button{
+			Text: text,
+			ClickHandler: func(*Button) {
+				choose(choice)
+			},
+		}
+	}
+
+	d = newDialog(title, message, input, buttons...)
+
+	d.EventHandler = func(c *Container, ev escapebox.Event) bool {
+		if ev.Type != termbox.EventKey {
+			return false
+		}
+
+		switch ev.Key {
+		case termbox.KeyEnter:
+			choose(0)
+			return true
+		case termbox.KeyEsc:
+			choose(len(choices) - 1)
+			return true
+		}
+
+		return false
+	}
+
+	return d
+}
+
+// Create a Dialog showing a message with an OK button. done is called after
+// it's closed and may be nil. Show it with ShowDialog.
+func MessageBox(title, message string, done func()) *Dialog {
+	return newChoiceDialog(title, message, nil, []string{"OK"},
+		func(int) {
+			if done != nil {
+				done()
+			}
+		})
+}
+
+// Create a Dialog asking a yes/no question. done is called with the answer
+// after it's closed; Esc counts as no. Show it with ShowDialog.
+func Confirm(title, question string, done func(yes bool)) *Dialog {
+	return newChoiceDialog(title, question, nil, []string{"Yes", "No"},
+		func(choice int) {
+			if done != nil {
+				done(choice == 0)
+			}
+		})
+}
+
+// Create a Dialog asking for a line of text, starting with value. done is
+// called after it's closed with the text and whether it was accepted with OK
+// rather than cancelled. Show it with ShowDialog.
+func Prompt(title, question, value string,
+	done func(value string, ok bool)) *Dialog {
+	input := &TextBox{Value: value}
+
+	d := newChoiceDialog(title, question, input,
+		[]string{"OK", "Cancel"}, func(choice int) {
+			if done != nil {
+				done(input.Value, choice == 0)
+			}
+		})
+
+	// Start with the caret after the text
+	input.HandleEvent(escapebox.Event{
+		Event: termbox.Event{Type: termbox.EventKey, Key: termbox.KeyEnd},
+	})
+
+	return d
+}
@@ -0,0 +1,62 @@
+package tui_test
+
+import (
+	"github.com/briansteffens/escapebox"
+	"github.com/briansteffens/tui"
+	"github.com/briansteffens/tui/tuitest"
+	"github.com/nsf/termbox-go"
+	"strings"
+	"testing"
+)
+
+func TestPromptDialog(t *testing.T) {
+	answers := []string{}
+
+	box := &tui.TextBox{Bounds: tui.Rect{Width: 40, Height: 3}}
+
+	prompt := func(c *tui.Container, ev escapebox.Event) bool {
+		if ev.Key != termbox.KeyF2 {
+			return false
+		}
+
+		c.ShowDialog(tui.Prompt("Name", "Who are you?", "bo",
+			func(value string, ok bool) {
+				if ok {
+					answers = append(answers, value)
+				}
+			}))
+		return true
+	}
+
+	newPromptContainer := func() *tui.Container {
+		return &tui.Container{
+			Controls:            []tui.Control{box},
+			KeyBindingFocusNext: tui.KeyBinding{Key: termbox.KeyTab},
+			EventHandler:        prompt,
+		}
+	}
+
+	s := tuitest.Run(t, newPromptContainer(), 40, 12,
+		tuitest.Key(termbox.KeyF2))
+
+	if !strings.Contains(s.Text(), "Who are you?") {
+		t.Errorf("expected the prompt to be shown, got\n%s", s.Text())
+	}
+
+	// Typing goes to the Dialog while it's open, and focus comes back to
+	// the TextBox once it's closed
+	events := append([]escapebox.Event{tuitest.Key(termbox.KeyF2)},
+		tuitest.Type("b")...)
+	events = append(events, tuitest.Key(termbox.KeyTab),
+		tuitest.Key(termbox.KeyEnter), tuitest.Char('x'))
+	s = tuitest.Run(t, newPromptContainer(), 40, 12, events...)
+
+	if len(answers) != 1 || answers[0] != "bob" || box.Value != "x" {
+		t.Errorf("unexpected answers %v and value %q", answers,
+			box.Value)
+	}
+
+	if strings.Contains(s.Text(), "Who are you?") {
+		t.Errorf("expected the prompt to be closed, got\n%s", s.Text())
+	}
+}
@@ -3,7 +3,6 @@ package tui
 import (
 	"errors"
 	"fmt"
-	"github.com/nsf/termbox-go"
 	"golang.org/x/text/unicode/norm"
 	"unicode/utf8"
 )
@@ -11,13 +10,24 @@ import (
 // A DrawTarget represents a drawable portion of the terminal window. Drawing
 // with methods like SetCell and Print will automatically translate from local
 // coordinates to screen coordinates and clip drawing to the drawable region.
-// It can be further subdivided by calling ChildDrawTarget().
+// It can be further subdivided by calling Slice().
+//
+// Part of a DrawTarget may be hidden, for example when it's a control
+// scrolled partly out of a ScrollView. Drawing to the hidden part is silently
+// skipped.
 type DrawTarget struct {
 	Width  int
 	Height int
 
+	screen     Screen
 	offsetLeft int
 	offsetTop  int
+
+	// The visible part of the screen, in screen coordinates
+	clip Rect
+
+	// Errors reported while drawing, shared by a DrawTarget and its slices
+	errs *[]error
 }
 
 // The location and size of the drawable area in local coordinates.
@@ -33,7 +43,7 @@ func (target *DrawTarget) Bounds() *Rect {
 // Set one terminal cell. If (x, y) is out of bounds, an error will be returned
 // and the terminal will be unchanged.
 func (target *DrawTarget) SetCell(x, y int,
-	foreground, background termbox.Attribute, char rune) error {
+	foreground, background Color, char rune) error {
 	if !target.Bounds().ContainsPoint(x, y) {
 		return errors.New(
 			"Coordinates are out of bounds for the DrawTarget")
@@ -41,16 +51,53 @@ func (target *DrawTarget) SetCell(x, y int,
 
 	globalX, globalY := target.localToScreenCoords(x, y)
 
-	termbox.SetCell(globalX, globalY, char, foreground, background)
+	if target.clip.ContainsPoint(globalX, globalY) {
+		target.screen.SetCell(globalX, globalY, char, foreground,
+			background)
+	}
 
 	return nil
 }
 
+// Move the terminal cursor to (x, y). If (x, y) is out of bounds, an error
+// will be returned and the cursor will be unchanged. If (x, y) is hidden, the
+// cursor is hidden.
+func (target *DrawTarget) SetCursor(x, y int) error {
+	if !target.Bounds().ContainsPoint(x, y) {
+		return errors.New(
+			"Coordinates are out of bounds for the DrawTarget")
+	}
+
+	globalX, globalY := target.localToScreenCoords(x, y)
+
+	if target.clip.ContainsPoint(globalX, globalY) {
+		target.screen.SetCursor(globalX, globalY)
+	} else {
+		target.screen.HideCursor()
+	}
+
+	return nil
+}
+
+// Hide the terminal cursor.
+func (target *DrawTarget) HideCursor() {
+	target.screen.HideCursor()
+}
+
+// Blank out the whole drawable region.
+func (target *DrawTarget) Clear() {
+	for y := 0; y < target.Height; y++ {
+		for x := 0; x < target.Width; x++ {
+			target.SetCell(x, y, ColorDefault, ColorDefault, ' ')
+		}
+	}
+}
+
 // Write formatted text to the terminal using the "fmt" package formatting
 // style. The text will be automatically clipped to the DrawTarget's drawable
 // region.
 func (target *DrawTarget) Print(x, y int,
-	foreground, background termbox.Attribute, text string,
+	foreground, background Color, text string,
 	args ...interface{}) {
 	formatted := fmt.Sprintf(text, args...)
 	normalized := normalizeString(formatted)
@@ -61,22 +108,52 @@ func (target *DrawTarget) Print(x, y int,
 
 // Create a DrawTarget which allows drawing to a portion of the parent's
 // DrawTarget area. childBounds should be specified in the parent's local
-// coordinates.
+// coordinates. Whatever part of childBounds lies outside the parent, or in a
+// hidden part of it, is hidden in the child. An error is only returned if
+// childBounds has a negative size.
 //
 // Note: this is mostly needed if you're writing a control that contains other
 // controls.
 func (parent *DrawTarget) Slice(childBounds *Rect) (*DrawTarget, error) {
+	if childBounds.Width < 0 || childBounds.Height < 0 {
+		logger.Warn("child bounds have a negative size",
+			"parent", *parent.Bounds(), "child", *childBounds)
+		return nil, errors.New("Provided child bounds have a " +
+			"negative size.")
+	}
+
 	if !parent.Bounds().ContainsRect(childBounds) {
-		return nil, errors.New("Provided child bounds would exceed " +
-			"the parent's dimensions.")
+		logger.Debug("child bounds exceed the parent, clipping",
+			"parent", *parent.Bounds(), "child", *childBounds)
 	}
 
-	return &DrawTarget{
+	child := &DrawTarget{
+		screen:     parent.screen,
+		errs:       parent.errs,
 		offsetLeft: parent.offsetLeft + childBounds.Left,
 		offsetTop:  parent.offsetTop + childBounds.Top,
 		Width:      childBounds.Width,
 		Height:     childBounds.Height,
-	}, nil
+	}
+
+	onScreen := Rect{
+		Left:   child.offsetLeft,
+		Top:    child.offsetTop,
+		Width:  child.Width,
+		Height: child.Height,
+	}
+
+	child.clip = parent.clip.Intersection(&onScreen)
+
+	return child, nil
+}
+
+// Record a problem encountered while drawing. Drawing carries on, and Refresh
+// returns everything reported.
+func (target *DrawTarget) ReportError(err error) {
+	if target.errs != nil {
+		*target.errs = append(*target.errs, err)
+	}
 }
 
 func (target *DrawTarget) localToScreenCoords(x, y int) (int, int) {
@@ -116,14 +193,17 @@ func normalizeString(input string) []rune {
 	return output
 }
 
-// Create a DrawTarget that allows drawing to the entire terminal window.
-func fullTerminalDrawTarget() *DrawTarget {
-	terminalWidth, terminalHeight := termbox.Size()
+// Create a DrawTarget that allows drawing to the entire screen.
+func fullScreenDrawTarget(s Screen) *DrawTarget {
+	screenWidth, screenHeight := s.Size()
 
 	return &DrawTarget{
-		Width:      terminalWidth,
-		Height:     terminalHeight,
+		Width:      screenWidth,
+		Height:     screenHeight,
+		screen:     s,
 		offsetLeft: 0,
 		offsetTop:  0,
+		clip:       Rect{Width: screenWidth, Height: screenHeight},
+		errs:       &[]error{},
 	}
 }
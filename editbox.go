@@ -27,10 +27,14 @@ const QuoteDouble rune = '"'
 
 type Char struct {
 	Char rune
-	Fg   termbox.Attribute
-	Bg   termbox.Attribute
+
+	// Explicit colors. When left at zero, the colors come from the theme
+	// based on the highlighter data below.
+	Fg Color
+	Bg Color
 
 	// Highlighter data
+	Token   Token
 	Quote   rune
 	Escaped bool
 }
@@ -40,13 +44,36 @@ func (c *Char) clone() Char {
 		Char:    c.Char,
 		Fg:      c.Fg,
 		Bg:      c.Bg,
+		Token:   c.Token,
 		Quote:   c.Quote,
 		Escaped: c.Escaped,
 	}
 }
 
-const colorKeyword termbox.Attribute = termbox.ColorBlue
-const colorType termbox.Attribute = termbox.ColorRed
+// Look up the theme style for a character based on its highlighter data,
+// then apply any explicit colors it has.
+func (c *Char) style() Style {
+	style := theme.Normal
+
+	switch {
+	case c.Token == TokenKeyword:
+		style = theme.Keyword
+	case c.Token == TokenType:
+		style = theme.Type
+	case c.Quote != QuoteNone:
+		style = theme.String
+	}
+
+	if c.Fg != 0 {
+		style.Fg = c.Fg
+	}
+
+	if c.Bg != 0 {
+		style.Bg = c.Bg
+	}
+
+	return style
+}
 
 type Token int
 
@@ -63,6 +90,8 @@ type CursorMovedEvent func(*EditBox)
 type Highlighter func(*EditBox)
 
 type EditBox struct {
+	Invalidation
+
 	Bounds        Rect
 	Lines         [][]Char
 	OnTextChanged TextChangedEvent
@@ -191,13 +220,7 @@ func (e *EditBox) SetText(raw string) {
 		isEnd := i == len(raw)-1
 
 		if c != '\n' {
-			char := Char{
-				Char: c,
-				Fg:   termbox.ColorWhite,
-				Bg:   termbox.ColorBlack,
-			}
-
-			line = append(line, char)
+			line = append(line, Char{Char: c})
 		}
 
 		if c == '\n' || isEnd {
@@ -210,7 +233,9 @@ func (e *EditBox) SetText(raw string) {
 }
 
 func (e *EditBox) Draw(target *DrawTarget) {
-	textWidth := e.Bounds.Width
+	// Lines wrap at one character when there's no room for any, so a
+	// squashed EditBox draws nothing instead of dividing by zero.
+	textWidth := max(1, e.Bounds.Width)
 	textHeight := e.Bounds.Height - 1 // Bottom line free for modes/notices
 
 	visualLineStart := e.visualLineStart
@@ -266,39 +291,40 @@ func (e *EditBox) Draw(target *DrawTarget) {
 
 	for l := e.scroll; l < scrollEnd; l++ {
 		for c, ch := range virtualLines[l] {
-			fg := ch.Fg
-			bg := ch.Bg
+			style := ch.style()
 
 			if l >= virtualVisualLineStart &&
 				l <= virtualVisualLineStop {
-				bg = termbox.ColorYellow
-				fg = termbox.ColorBlack
+				style = theme.VisualSelection
 			}
 
-			target.SetCell(c, l-e.scroll, fg, bg, ch.Char)
+			target.SetCell(c, l-e.scroll, style.Fg, style.Bg, ch.Char)
 		}
 	}
 
 	if e.focus {
-		termbox.SetCursor(e.Bounds.Left+cursorCol,
-			e.Bounds.Top+cursorRow-e.scroll)
+		target.SetCursor(cursorCol, cursorRow-e.scroll)
 	}
 
 	if e.mode == InsertMode {
-		target.Print(0, e.Bounds.Height-1, termbox.ColorDefault,
-			termbox.ColorDefault, "-- INSERT --")
+		target.Print(0, e.Bounds.Height-1, theme.StatusLine.Fg,
+			theme.StatusLine.Bg, "-- INSERT --")
 	}
 }
 
 func (e *EditBox) SetFocus() {
 	e.focus = true
+	e.Invalidate()
 }
 
 func (e *EditBox) UnsetFocus() {
 	e.focus = false
+	e.Invalidate()
 }
 
 func (e *EditBox) fireTextChanged() {
+	e.Invalidate()
+
 	if e.Highlighter != nil {
 		e.Highlighter(e)
 	}
@@ -599,8 +625,45 @@ func (e *EditBox) previousWord() {
 	}
 }
 
+// Map a position in the wrapped (virtual) lines shown by Draw back to a line
+// and char index. Positions past the end of the text map to the end of the
+// last line.
+func (e *EditBox) virtualToCursor(row, col int) (int, int) {
+	textWidth := e.Bounds.Width
+
+	for lineIndex, line := range e.Lines {
+		virtualLineCount := len(line)/textWidth + 1
+
+		if row < virtualLineCount {
+			return lineIndex, row*textWidth + col
+		}
+
+		row -= virtualLineCount
+	}
+
+	lastLine := len(e.Lines) - 1
+	return lastLine, len(e.Lines[lastLine])
+}
+
+func (e *EditBox) handleMouseEvent(ev escapebox.Event) bool {
+	if direction := wheelDirection(ev); direction != 0 {
+		e.cursorLine += direction * wheelLines
+		return true
+	}
+
+	// The bottom line is for modes/notices, not text
+	if !leftClick(ev) || ev.MouseY >= e.Bounds.Height-1 {
+		return false
+	}
+
+	e.cursorLine, e.cursorChar = e.virtualToCursor(e.scroll+ev.MouseY,
+		ev.MouseX)
+
+	return true
+}
+
 func (e *EditBox) HandleEvent(ev escapebox.Event) bool {
-	if ev.Type != termbox.EventKey {
+	if ev.Type != termbox.EventKey && ev.Type != termbox.EventMouse {
 		return false
 	}
 
@@ -609,6 +672,14 @@ func (e *EditBox) HandleEvent(ev escapebox.Event) bool {
 
 	handled := false
 
+	if ev.Type == termbox.EventMouse {
+		if !e.handleMouseEvent(ev) {
+			return false
+		}
+
+		handled = true
+	}
+
 	if !handled && ev.Key == termbox.KeyHome {
 		e.cursorChar = 0
 		handled = true
@@ -684,32 +755,47 @@ func (e *EditBox) HandleEvent(ev escapebox.Event) bool {
 		e.fireCursorMoved()
 	}
 
+	// Mode changes, chords and the like don't move the cursor but can
+	// still change what's displayed
+	if handled {
+		e.Invalidate()
+	}
+
 	return handled
 }
 
 func (e *EditBox) fireCursorMoved() {
+	e.Invalidate()
+
 	if e.OnCursorMoved != nil {
 		e.OnCursorMoved(e)
 	}
 }
 
-func (e *EditBox) validateLineRange(start, stop int) {
+func (e *EditBox) validateLineRange(start, stop int) error {
 	if start > stop {
-		panic("Can't delete this range")
+		return errors.New("Can't delete this range")
 	}
 
 	if start < 0 || stop < 0 ||
 		start >= len(e.Lines) || stop >= len(e.Lines) {
-		panic("Line deletion out of range")
+		return errors.New("Line deletion out of range")
 	}
 
 	if stop-start+1 > len(e.Lines) {
-		panic("Can't delete more lines than currently exist")
+		return errors.New(
+			"Can't delete more lines than currently exist")
 	}
+
+	return nil
 }
 
+// Delete lines start to stop. Callers check the range first, usually by
+// copying the lines to the clipboard; an invalid range does nothing.
 func (e *EditBox) deleteLines(start, stop int) {
-	e.validateLineRange(start, stop)
+	if e.validateLineRange(start, stop) != nil {
+		return
+	}
 
 	toDelete := stop - start + 1
 	newSize := len(e.Lines) - toDelete
@@ -742,8 +828,10 @@ func (e *EditBox) deleteLines(start, stop int) {
 	e.fireCursorMoved()
 }
 
-func (e *EditBox) copyLinesToClipBoard(start, stop int) {
-	e.validateLineRange(start, stop)
+func (e *EditBox) copyLinesToClipBoard(start, stop int) error {
+	if err := e.validateLineRange(start, stop); err != nil {
+		return err
+	}
 
 	e.clipBoard = [][]Char{}
 
@@ -756,6 +844,8 @@ func (e *EditBox) copyLinesToClipBoard(start, stop int) {
 
 		e.clipBoard = append(e.clipBoard, line)
 	}
+
+	return nil
 }
 
 func (e *EditBox) paste() {
@@ -784,8 +874,10 @@ func (e *EditBox) paste() {
 func (e *EditBox) handleChord_d() bool {
 	if e.chord[1].Ch == 'd' {
 		// Delete current line
-		e.copyLinesToClipBoard(e.cursorLine, e.cursorLine)
-		e.deleteLines(e.cursorLine, e.cursorLine)
+		err := e.copyLinesToClipBoard(e.cursorLine, e.cursorLine)
+		if err == nil {
+			e.deleteLines(e.cursorLine, e.cursorLine)
+		}
 	}
 
 	return true
@@ -1004,9 +1096,9 @@ func (e *EditBox) handleVisualLineModeEvent(ev escapebox.Event) bool {
 			stop = temp
 		}
 
-		e.copyLinesToClipBoard(start, stop)
+		err := e.copyLinesToClipBoard(start, stop)
 
-		if ev.Ch == 'd' {
+		if err == nil && ev.Ch == 'd' {
 			e.deleteLines(start, stop)
 		}
 
@@ -1112,18 +1204,9 @@ func BasicHighlighter(e *EditBox) {
 				tokenType = e.Dialect(word)
 			}
 
-			wordColor := termbox.ColorWhite
-
-			switch tokenType {
-			case TokenKeyword:
-				wordColor = colorKeyword
-			case TokenType:
-				wordColor = colorType
-			}
-
-			if wordColor != termbox.ColorWhite {
+			if tokenType != TokenNone {
 				for j := i - 1; j >= i-len(word)-1; j-- {
-					chars[j].Fg = wordColor
+					chars[j].Token = tokenType
 				}
 			}
 
@@ -1132,12 +1215,9 @@ func BasicHighlighter(e *EditBox) {
 			word += string(cur.Char)
 		}
 
-		// Color quotes
-		if quote != QuoteNone {
-			cur.Fg = termbox.ColorGreen
-		} else {
-			cur.Fg = termbox.ColorWhite
-		}
+		// Reset the token, since the word this character was part of
+		// may have changed. Quotes are colored from cur.Quote.
+		cur.Token = TokenNone
 
 		// End quote
 		if isCurQuote && quote != QuoteNone && !quoteToggledThisLoop &&
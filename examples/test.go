@@ -1,18 +1,17 @@
 package main
 
 import (
+	"fmt"
 	"github.com/nsf/termbox-go"
 	"github.com/briansteffens/tui"
+	"os"
 )
 
 func buttonClickHandler(b *tui.Button) {
 	panic("clicked!")
 }
 
-func main() {
-	tui.Init()
-	defer tui.Close()
-
+func newContainer() *tui.Container {
 	edit1 := tui.EditBox {
 		Bounds: tui.Rect { Left: 2, Top: 6, Width: 30, Height: 10 },
 	}
@@ -27,11 +26,13 @@ func main() {
 	t := tui.TextBox {
 		Bounds: tui.Rect { Left: 2, Top: 2, Width: 5, Height: 3 },
 		Value: "12",
+		Border: tui.BorderSingle,
 	}
 
 	t2 := tui.TextBox {
 		Bounds: tui.Rect { Left: 10, Top: 2, Width: 15, Height: 3},
 		Value: "Greetings!",
+		Border: tui.BorderSingle,
 	}
 
 	checkbox1 := tui.CheckBox {
@@ -40,9 +41,10 @@ func main() {
 	}
 
 	button1 := tui.Button {
-		Bounds: tui.Rect { Left: 27, Top: 2, Width: 10, Height: 3},
+		Bounds: tui.Rect { Left: 27, Top: 2, Width: 13, Height: 3},
 		Text: "Continue!",
 		ClickHandler: buttonClickHandler,
+		Border: tui.BorderRounded,
 	}
 
 	dv := tui.DetailView {
@@ -58,12 +60,11 @@ func main() {
 			[]string { "13", "C", "Such an informative table" },
 			[]string { "17", "D", "Abcdefghijklmnopqrst" },
 		},
-		RowBg: termbox.Attribute(0),
-		RowBgAlt: termbox.Attribute(236),
-		SelectedBg: termbox.Attribute(22),
+		RowBgAlt: tui.PaletteColor(235),
+		SelectedBg: tui.PaletteColor(21),
 	}
 
-	c := tui.Container {
+	return &tui.Container {
 		Controls: []tui.Control {&t, &dv, &edit1, &l, &t2, &checkbox1,
 					 &button1},
 		KeyBindingExit: tui.KeyBinding { Key: termbox.KeyCtrlC },
@@ -71,7 +72,24 @@ func main() {
 		KeyBindingFocusPrevious: tui.KeyBinding {
 			Seq: tui.SeqShiftTab,
 		},
+		KeyBindingFocusUp: tui.KeyBinding { Seq: tui.SeqAltUp },
+		KeyBindingFocusDown: tui.KeyBinding { Seq: tui.SeqAltDown },
+		KeyBindingFocusLeft: tui.KeyBinding { Seq: tui.SeqAltLeft },
+		KeyBindingFocusRight: tui.KeyBinding { Seq: tui.SeqAltRight },
+	}
+}
+
+func main() {
+	if err := tui.Init(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
 
-	tui.MainLoop(&c)
+	err := tui.MainLoop(newContainer())
+	tui.Close()
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"github.com/briansteffens/escapebox"
+	"github.com/briansteffens/tui"
+	"github.com/briansteffens/tui/tuitest"
+	"github.com/nsf/termbox-go"
+	"testing"
+)
+
+func TestInitialScreen(t *testing.T) {
+	s := tuitest.Run(t, newContainer(), 60, 20)
+
+	tuitest.AssertGolden(t, s, "initial")
+}
+
+func TestEditBoxTyping(t *testing.T) {
+	events := []escapebox.Event{tuitest.Key(termbox.KeyTab),
+		tuitest.Char('A')}
+	events = append(events, tuitest.Type(" select 1")...)
+	events = append(events, tuitest.Key(termbox.KeyEsc))
+
+	s := tuitest.Run(t, newContainer(), 60, 20, events...)
+
+	tuitest.AssertLine(t, s, 6, "  abcdefgh select 1")
+	tuitest.AssertGolden(t, s, "editing")
+}
+
+func TestDetailViewSelection(t *testing.T) {
+	s := tuitest.Run(t, newContainer(), 60, 20, tuitest.Char('j'),
+		tuitest.Char('l'))
+
+	// The DetailView has focus first; the cursor moved to row 1, column 1
+	tuitest.AssertStyle(t, s, 5, 18, tui.ColorWhite, tui.PaletteColor(21))
+	tuitest.AssertStyle(t, s, 2, 18, tui.ColorWhite, tui.ColorDefault)
+}
+
+func TestFocusCycle(t *testing.T) {
+	s := tuitest.Run(t, newContainer(), 60, 20,
+		tuitest.Key(termbox.KeyTab), tuitest.Key(termbox.KeyTab),
+		tuitest.Seq(tui.SeqShiftTab))
+
+	// Back on the EditBox, with the cursor on its first character
+	x, y, visible := s.Cursor()
+	if !visible || x != 2 || y != 6 {
+		t.Errorf("expected cursor at 2,6, got %d,%d (visible: %t)",
+			x, y, visible)
+	}
+}
+
+func TestMouseClick(t *testing.T) {
+	s := tuitest.Run(t, newContainer(), 60, 20, tuitest.Click(28, 1),
+		tuitest.Click(14, 3))
+
+	tuitest.AssertLine(t, s, 1,
+		"  Greetings:               [X] Enable the whateverthing")
+
+	// The second click focused the TextBox and placed the caret
+	x, y, visible := s.Cursor()
+	if !visible || x != 14 || y != 3 {
+		t.Errorf("expected cursor at 14,3, got %d,%d (visible: %t)",
+			x, y, visible)
+	}
+}
+
+func TestUnhandledEventSkipsRedraw(t *testing.T) {
+	// The DetailView has focus and ignores 'z'
+	s := tuitest.Run(t, newContainer(), 60, 20, tuitest.Char('z'))
+
+	if s.Flushes() != 1 {
+		t.Errorf("expected only the initial flush, got %d", s.Flushes())
+	}
+}
+
+func TestPanicIsReturned(t *testing.T) {
+	// Clicking the button panics
+	s := tuitest.NewScreen(60, 20, tuitest.Click(30, 3))
+
+	previous := tui.CurrentScreen()
+	tui.SetScreen(s)
+	defer tui.SetScreen(previous)
+
+	err := tui.MainLoop(newContainer())
+
+	panicErr, ok := err.(*tui.PanicError)
+	if !ok || panicErr.Value != "clicked!" {
+		t.Errorf("expected the button's panic, got %v", err)
+	}
+}
+
+func TestLightTheme(t *testing.T) {
+	tui.SetTheme(tui.LightTheme())
+	defer tui.SetTheme(tui.DefaultTheme())
+
+	s := tuitest.Run(t, newContainer(), 60, 20)
+
+	// The "Greetings:" label
+	tuitest.AssertStyle(t, s, 2, 1, tui.ColorBlack, tui.ColorWhite)
+}
+
+func TestSpatialFocus(t *testing.T) {
+	// From the DetailView up to the EditBox, up to the TextBox best lined
+	// up with it and right to the button
+	s := tuitest.Run(t, newContainer(), 60, 20,
+		tuitest.Seq(tui.SeqAltUp), tuitest.Seq(tui.SeqAltUp),
+		tuitest.Char('!'), tuitest.Seq(tui.SeqAltRight))
+
+	tuitest.AssertLine(t, s, 3, "  │12 │   │!Greetings!  │  │ Continue! │")
+
+	// The button shows the cursor just before its text
+	x, y, visible := s.Cursor()
+	if !visible || x != 28 || y != 3 {
+		t.Errorf("expected cursor at 28,3, got %d,%d (visible: %t)",
+			x, y, visible)
+	}
+}
+
+func TestConfirmDialog(t *testing.T) {
+	answers := []bool{}
+
+	newConfirmContainer := func() *tui.Container {
+		c := newContainer()
+		c.EventHandler = func(c *tui.Container, ev escapebox.Event) bool {
+			if ev.Ch != 'q' {
+				return false
+			}
+
+			c.ShowDialog(tui.Confirm("Quit", "Really quit?",
+				func(yes bool) {
+					answers = append(answers, yes)
+				}))
+			return true
+		}
+		return c
+	}
+
+	s := tuitest.Run(t, newConfirmContainer(), 60, 20, tuitest.Char('q'))
+	tuitest.AssertGolden(t, s, "confirm")
+
+	// Tab moves to "No" without leaving the dialog. The DetailView gets
+	// focus back afterwards, so 'j' moves its cursor.
+	c := newConfirmContainer()
+	s = tuitest.Run(t, c, 60, 20, tuitest.Char('q'),
+		tuitest.Key(termbox.KeyTab), tuitest.Key(termbox.KeyEnter),
+		tuitest.Char('j'))
+
+	if len(answers) != 1 || answers[0] {
+		t.Errorf("expected a single no, got %v", answers)
+	}
+
+	if _, ok := c.Focused.(*tui.DetailView); !ok {
+		t.Errorf("expected focus back on the DetailView, got %T",
+			c.Focused)
+	}
+
+	tuitest.AssertStyle(t, s, 2, 18, tui.ColorWhite, tui.PaletteColor(21))
+}
@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"github.com/nsf/termbox-go"
+	"sync"
+	"time"
+)
+
+// The termbox attribute drawn for a color on a terminal with the given color
+// support.
+func (c Color) ToTermbox(mode ColorMode, foreground bool) termbox.Attribute {
+	return c.toTermbox(mode, foreground)
+}
+
+// The palette index in [first, last] which looks closest to a color.
+var NearestPaletteIndex = nearestPaletteIndex
+
+// A clock for tests which only moves on when Advance is called. Timers use it
+// instead of real time between UseFakeClock and the function it returns.
+type FakeClock struct {
+	lock   sync.Mutex
+	now    time.Duration
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	clock   *FakeClock
+	at      time.Duration
+	f       func()
+	stopped bool
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.lock.Lock()
+	defer t.clock.lock.Unlock()
+
+	wasRunning := !t.stopped
+	t.stopped = true
+
+	return wasRunning
+}
+
+// Make Timers use a new FakeClock. Call the returned function to go back to
+// real time.
+func UseFakeClock() (*FakeClock, func()) {
+	clock := &FakeClock{}
+	previous := afterFunc
+
+	afterFunc = func(d time.Duration, f func()) stopper {
+		clock.lock.Lock()
+		defer clock.lock.Unlock()
+
+		t := &fakeTimer{clock: clock, at: clock.now + d, f: f}
+		clock.timers = append(clock.timers, t)
+
+		return t
+	}
+
+	return clock, func() {
+		afterFunc = previous
+	}
+}
+
+// Move the clock on by d, running the functions of the timers which come
+// due, earliest first.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	end := c.now + d
+
+	for {
+		var next *fakeTimer
+
+		for _, t := range c.timers {
+			if !t.stopped && t.at <= end &&
+				(next == nil || t.at < next.at) {
+				next = t
+			}
+		}
+
+		if next == nil {
+			break
+		}
+
+		next.stopped = true
+		c.now = next.at
+
+		c.lock.Unlock()
+		next.f()
+		c.lock.Lock()
+	}
+
+	c.now = end
+	c.lock.Unlock()
+}
@@ -0,0 +1,77 @@
+package tui
+
+// A Frame draws a border and optional title around another control. Content
+// is resized to fill the inside of the border, and the border is highlighted
+// while focus is anywhere inside Content.
+type Frame struct {
+	Invalidation
+
+	Bounds Rect
+
+	// BorderNone is drawn as BorderSingle
+	Border BorderStyle
+
+	Title   string
+	Content Control
+
+	focusWithin bool
+}
+
+func (f *Frame) GetBounds() *Rect {
+	return &f.Bounds
+}
+
+// Fit Content inside the border.
+func (f *Frame) arrange() {
+	if f.Content == nil {
+		return
+	}
+
+	*f.Content.GetBounds() = Rect{
+		Left:   1,
+		Top:    1,
+		Width:  max(0, f.Bounds.Width-2),
+		Height: max(0, f.Bounds.Height-2),
+	}
+}
+
+func (f *Frame) Children() []Control {
+	if f.Content == nil {
+		return []Control{}
+	}
+
+	f.arrange()
+	return []Control{f.Content}
+}
+
+func (f *Frame) focusChanged(focused Focusable) {
+	focus := focused != nil
+
+	if focus != f.focusWithin {
+		f.focusWithin = focus
+		f.Invalidate()
+	}
+}
+
+// Check whether the Frame or its Content need to be redrawn.
+func (f *Frame) Invalid() bool {
+	return f.Invalidation.Invalid() ||
+		(f.Content != nil && needsDraw(f.Content))
+}
+
+// Draw the border and all of Content.
+func (f *Frame) Draw(target *DrawTarget) {
+	border := f.Border
+	if border == BorderNone {
+		border = BorderSingle
+	}
+
+	target.Border(border, borderStyle(f.focusWithin), f.Title)
+
+	if f.Content == nil {
+		return
+	}
+
+	f.arrange()
+	drawChild(target, f.Content)
+}
@@ -0,0 +1,28 @@
+package tui_test
+
+import (
+	"github.com/briansteffens/tui"
+	"github.com/briansteffens/tui/tuitest"
+	"testing"
+)
+
+func TestFrameHighlightsFocus(t *testing.T) {
+	input := &tui.TextBox{}
+	frame := &tui.Frame{
+		Bounds:  tui.Rect{Width: 20, Height: 5},
+		Border:  tui.BorderDouble,
+		Title:   "Query",
+		Content: input,
+	}
+
+	s := tuitest.Run(t, &tui.Container{Controls: []tui.Control{frame}},
+		30, 6)
+
+	tuitest.AssertLine(t, s, 0, "╔═ Query ══════════╗")
+	tuitest.AssertStyle(t, s, 0, 0, tui.ColorLightCyan, tui.ColorBlack)
+
+	if *input.GetBounds() != (tui.Rect{Left: 1, Top: 1, Width: 18,
+		Height: 3}) {
+		t.Errorf("unexpected content bounds %+v", *input.GetBounds())
+	}
+}
@@ -0,0 +1,49 @@
+package tui
+
+// Invalidation tracks whether a control needs to be redrawn. Embed it in a
+// control and call Invalidate whenever something affecting the control's
+// appearance changes. Containers only redraw controls which are invalid, and
+// any controls overlapping them, and Refresh does nothing at all if no
+// control is invalid.
+//
+// The built-in controls invalidate themselves when they handle input or
+// their methods are called. After changing their exported fields directly
+// (for example DetailView.Rows or Label.Text), call Invalidate yourself.
+//
+// The zero value is invalid, so every control gets drawn at least once.
+type Invalidation struct {
+	valid bool
+}
+
+// Mark the control as needing to be redrawn.
+func (i *Invalidation) Invalidate() {
+	i.valid = false
+}
+
+// Check whether the control needs to be redrawn.
+func (i *Invalidation) Invalid() bool {
+	return !i.valid
+}
+
+func (i *Invalidation) validate() {
+	i.valid = true
+}
+
+type invalidatable interface {
+	Invalid() bool
+	validate()
+}
+
+// Check whether a control needs to be redrawn. Controls which don't embed an
+// Invalidation are always redrawn.
+func needsDraw(c Control) bool {
+	i, ok := c.(invalidatable)
+	return !ok || i.Invalid()
+}
+
+// Mark a control as up to date after drawing it.
+func validate(c Control) {
+	if i, ok := c.(invalidatable); ok {
+		i.validate()
+	}
+}
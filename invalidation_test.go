@@ -0,0 +1,29 @@
+package tui_test
+
+import (
+	"github.com/briansteffens/tui"
+	"github.com/briansteffens/tui/tuitest"
+	"github.com/nsf/termbox-go"
+	"testing"
+)
+
+func TestUnhandledEventSkipsRedraw(t *testing.T) {
+	box := &tui.TextBox{Bounds: tui.Rect{Width: 20, Height: 3}}
+	c := &tui.Container{Controls: []tui.Control{box}}
+
+	// The TextBox ignores F5, so nothing is drawn after the first refresh
+	s := tuitest.Run(t, c, 20, 3, tuitest.Key(termbox.KeyF5))
+
+	if s.Flushes() != 1 {
+		t.Errorf("expected only the initial flush, got %d", s.Flushes())
+	}
+
+	// Typing changes the TextBox, which is redrawn
+	s = tuitest.Run(t, c, 20, 3, tuitest.Char('x'))
+
+	if s.Flushes() != 2 {
+		t.Errorf("expected a flush after typing, got %d", s.Flushes())
+	}
+
+	tuitest.AssertLine(t, s, 1, " x")
+}
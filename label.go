@@ -1,10 +1,8 @@
 package tui
 
-import (
-	"github.com/nsf/termbox-go"
-)
-
 type Label struct {
+	Invalidation
+
 	Bounds Rect
 	Text   string
 }
@@ -14,5 +12,6 @@ func (l *Label) GetBounds() *Rect {
 }
 
 func (l *Label) Draw(target *DrawTarget) {
-	target.Print(0, 0, termbox.ColorWhite, termbox.ColorBlack, l.Text)
+	style := theme.Normal
+	target.Print(0, 0, style.Fg, style.Bg, l.Text)
 }
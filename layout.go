@@ -0,0 +1,277 @@
+package tui
+
+// A Layout sets the Bounds of a Container's controls to fit inside area.
+// Containers with a Layout re-arrange their controls whenever they're resized.
+type Layout interface {
+	Arrange(area Rect, controls []Control)
+}
+
+// How much space a control gets along a layout's axis. A Size with neither
+// Fixed nor Weight set fills the remaining space, like Weight 1.
+type Size struct {
+	// Exact number of cells, or nil if the size isn't fixed
+	Fixed *int
+
+	// Share of the space left over after fixed sizes and gaps, relative to
+	// the other weighted sizes
+	Weight int
+
+	// Limits on the computed size. Max is ignored if zero.
+	Min int
+	Max int
+}
+
+// A Size of exactly n cells.
+func Fixed(n int) Size {
+	return Size{Fixed: &n}
+}
+
+// A Size taking a share of the remaining space proportional to weight.
+func Weight(weight int) Size {
+	return Size{Weight: weight}
+}
+
+// A Size taking an equal share of the remaining space.
+var Fill = Size{}
+
+func (s Size) weight() int {
+	if s.Fixed != nil {
+		return 0
+	}
+
+	if s.Weight == 0 {
+		return 1
+	}
+
+	return s.Weight
+}
+
+func (s Size) clamp(n int) int {
+	if s.Max != 0 && n > s.Max {
+		n = s.Max
+	}
+
+	return max(n, s.Min)
+}
+
+// Empty space around the edges of a layout's area.
+type Padding struct {
+	Left, Top, Right, Bottom int
+}
+
+// The same padding on all four sides.
+func Pad(n int) Padding {
+	return Padding{Left: n, Top: n, Right: n, Bottom: n}
+}
+
+func (p Padding) apply(r Rect) Rect {
+	return Rect{
+		Left:   r.Left + p.Left,
+		Top:    r.Top + p.Top,
+		Width:  max(0, r.Width-p.Left-p.Right),
+		Height: max(0, r.Height-p.Top-p.Bottom),
+	}
+}
+
+// Split total cells between count slots separated by gap, returning the
+// offset and length of each. Missing sizes fill. Slots which don't fit are
+// cut short rather than extending past total.
+func distribute(total, gap, count int, sizes []Size) ([]int, []int) {
+	offsets := make([]int, count)
+	lengths := make([]int, count)
+
+	if count == 0 {
+		return offsets, lengths
+	}
+
+	size := func(i int) Size {
+		if i < len(sizes) {
+			return sizes[i]
+		}
+		return Fill
+	}
+
+	remaining := total - gap*(count-1)
+
+	// Fixed sizes first, then share what's left between weighted ones
+	flexible := []int{}
+
+	for i := 0; i < count; i++ {
+		s := size(i)
+
+		if s.weight() == 0 {
+			lengths[i] = s.clamp(*s.Fixed)
+			remaining -= lengths[i]
+		} else {
+			flexible = append(flexible, i)
+		}
+	}
+
+	// A weighted slot whose share breaks its Min or Max is fixed at that
+	// limit and the rest is shared again without it.
+	for len(flexible) > 0 {
+		totalWeight := 0
+		for _, i := range flexible {
+			totalWeight += size(i).weight()
+		}
+
+		space := max(0, remaining)
+		unclamped := []int{}
+		used := 0
+
+		for _, i := range flexible {
+			s := size(i)
+			share := space * s.weight() / totalWeight
+
+			if s.clamp(share) != share {
+				lengths[i] = s.clamp(share)
+				remaining -= lengths[i]
+				continue
+			}
+
+			lengths[i] = share
+			used += share
+			unclamped = append(unclamped, i)
+		}
+
+		if len(unclamped) == len(flexible) {
+			// Hand out what integer division left over, one cell
+			// at a time from the start.
+			for j := 0; j < space-used; j++ {
+				lengths[unclamped[j%len(unclamped)]]++
+			}
+			break
+		}
+
+		flexible = unclamped
+	}
+
+	offset := 0
+
+	for i := 0; i < count; i++ {
+		offsets[i] = min(offset, total)
+		lengths[i] = max(0, min(lengths[i], total-offsets[i]))
+		offset += lengths[i] + gap
+	}
+
+	return offsets, lengths
+}
+
+// Stacks controls from top to bottom, each as wide as the area.
+type VBox struct {
+	// The height of each control, in the same order as the controls.
+	// Controls without a Size fill.
+	Sizes []Size
+
+	Padding Padding
+	Gap     int
+}
+
+func (l *VBox) Arrange(area Rect, controls []Control) {
+	area = l.Padding.apply(area)
+	offsets, lengths := distribute(area.Height, l.Gap, len(controls),
+		l.Sizes)
+
+	for i, c := range controls {
+		*c.GetBounds() = Rect{
+			Left:   area.Left,
+			Top:    area.Top + offsets[i],
+			Width:  area.Width,
+			Height: lengths[i],
+		}
+	}
+}
+
+// Places controls side by side from left to right, each as tall as the area.
+type HBox struct {
+	// The width of each control, in the same order as the controls.
+	// Controls without a Size fill.
+	Sizes []Size
+
+	Padding Padding
+	Gap     int
+}
+
+func (l *HBox) Arrange(area Rect, controls []Control) {
+	area = l.Padding.apply(area)
+	offsets, lengths := distribute(area.Width, l.Gap, len(controls),
+		l.Sizes)
+
+	for i, c := range controls {
+		*c.GetBounds() = Rect{
+			Left:   area.Left + offsets[i],
+			Top:    area.Top,
+			Width:  lengths[i],
+			Height: area.Height,
+		}
+	}
+}
+
+// The position of a control in a Grid. Spans of zero count as one.
+type GridCell struct {
+	Column     int `json:"column" yaml:"column"`
+	Row        int `json:"row" yaml:"row"`
+	ColumnSpan int `json:"columnSpan" yaml:"columnSpan"`
+	RowSpan    int `json:"rowSpan" yaml:"rowSpan"`
+}
+
+// Places controls in the cells of a table. The number of columns and rows is
+// set by Columns and Rows.
+type Grid struct {
+	Columns []Size
+	Rows    []Size
+
+	// The cell of each control, in the same order as the controls.
+	// Controls without a GridCell are placed in the next cell, filling the
+	// grid row by row. Controls in cells outside the grid are given an
+	// empty size.
+	Cells []GridCell
+
+	Padding   Padding
+	ColumnGap int
+	RowGap    int
+}
+
+func (l *Grid) Arrange(area Rect, controls []Control) {
+	area = l.Padding.apply(area)
+
+	columns := max(1, len(l.Columns))
+	rows := max(1, len(l.Rows))
+
+	colOffsets, colWidths := distribute(area.Width, l.ColumnGap, columns,
+		l.Columns)
+	rowOffsets, rowHeights := distribute(area.Height, l.RowGap, rows,
+		l.Rows)
+
+	// Find the far edge of a span of columns or rows
+	end := func(offsets, lengths []int, first, span int) int {
+		last := min(first+max(1, span), len(offsets)) - 1
+		return offsets[last] + lengths[last]
+	}
+
+	for i, c := range controls {
+		cell := GridCell{Column: i % columns, Row: i / columns}
+		if i < len(l.Cells) {
+			cell = l.Cells[i]
+		}
+
+		// Controls outside the grid are squashed out of sight
+		if cell.Column < 0 || cell.Row < 0 || cell.Column >= columns ||
+			cell.Row >= rows {
+			*c.GetBounds() = Rect{Left: area.Left, Top: area.Top}
+			continue
+		}
+
+		left := colOffsets[cell.Column]
+		top := rowOffsets[cell.Row]
+		right := end(colOffsets, colWidths, cell.Column, cell.ColumnSpan)
+		bottom := end(rowOffsets, rowHeights, cell.Row, cell.RowSpan)
+
+		*c.GetBounds() = Rect{
+			Left:   area.Left + left,
+			Top:    area.Top + top,
+			Width:  right - left,
+			Height: bottom - top,
+		}
+	}
+}
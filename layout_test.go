@@ -0,0 +1,130 @@
+package tui_test
+
+import (
+	"github.com/briansteffens/tui"
+	"github.com/briansteffens/tui/tuitest"
+	"testing"
+)
+
+func TestVBoxFillsWindow(t *testing.T) {
+	edit := &tui.EditBox{}
+	dv := &tui.DetailView{
+		Columns: []tui.Column{{Name: "ID", Width: 3}},
+	}
+
+	c := &tui.Container{
+		Controls: []tui.Control{edit, dv},
+		Layout: &tui.VBox{
+			Sizes: []tui.Size{tui.Fill, {Weight: 1, Min: 8}},
+			Gap:   1,
+		},
+	}
+
+	tuitest.Run(t, c, 60, 20, tuitest.Resize(40, 11))
+
+	// 10 rows after the gap, split evenly until the DetailView's minimum
+	if *edit.GetBounds() != (tui.Rect{Width: 40, Height: 2}) {
+		t.Errorf("unexpected EditBox bounds %+v", *edit.GetBounds())
+	}
+
+	if *dv.GetBounds() != (tui.Rect{Top: 3, Width: 40, Height: 8}) {
+		t.Errorf("unexpected DetailView bounds %+v", *dv.GetBounds())
+	}
+}
+
+func TestGridSquashesControlsOutsideIt(t *testing.T) {
+	inside := &tui.Label{Text: "inside"}
+	left := &tui.Label{Text: "left"}
+	above := &tui.Label{Text: "above"}
+	below := &tui.Label{Text: "below"}
+
+	c := &tui.Container{
+		Controls: []tui.Control{inside, left, above, below},
+		Layout: &tui.Grid{
+			Columns: []tui.Size{tui.Fill, tui.Fill},
+			Rows:    []tui.Size{tui.Fill},
+			Cells: []tui.GridCell{{Column: 1}, {Column: -1},
+				{Row: -1}, {Row: 1}},
+		},
+	}
+
+	tuitest.Run(t, c, 20, 4)
+
+	if *inside.GetBounds() != (tui.Rect{Left: 10, Width: 10,
+		Height: 4}) {
+		t.Errorf("unexpected bounds %+v", *inside.GetBounds())
+	}
+
+	for _, l := range []*tui.Label{left, above, below} {
+		if *l.GetBounds() != (tui.Rect{}) {
+			t.Errorf("expected %s to be squashed, got %+v", l.Text,
+				*l.GetBounds())
+		}
+	}
+}
+
+func TestGridSquashedEditBox(t *testing.T) {
+	label := &tui.Label{Text: "only cell"}
+	edit := &tui.EditBox{}
+	edit.SetText("squashed")
+
+	c := &tui.Container{
+		Controls: []tui.Control{label, edit},
+		Layout:   &tui.Grid{},
+	}
+
+	// The EditBox has no cell, so it gets no space and draws nothing
+	s := tuitest.Run(t, c, 20, 4, tuitest.Char('i'), tuitest.Char('x'))
+
+	if *edit.GetBounds() != (tui.Rect{}) {
+		t.Errorf("expected the EditBox to be squashed, got %+v",
+			*edit.GetBounds())
+	}
+
+	tuitest.AssertLine(t, s, 0, "only cell")
+}
+
+func TestHBoxSqueezedTextBox(t *testing.T) {
+	text := &tui.TextBox{Value: "hello"}
+
+	c := &tui.Container{
+		Controls: []tui.Control{text},
+		Layout:   &tui.HBox{Padding: tui.Padding{Left: 5, Right: 4}},
+	}
+
+	// Padding leaves one column, too narrow for any text, but typing still
+	// works
+	tuitest.Run(t, c, 10, 3, tuitest.Char('!'))
+
+	if *text.GetBounds() != (tui.Rect{Left: 5, Width: 1, Height: 3}) {
+		t.Errorf("unexpected TextBox bounds %+v", *text.GetBounds())
+	}
+
+	if text.Value != "!hello" {
+		t.Errorf("unexpected value %q", text.Value)
+	}
+}
+
+func TestFixedZero(t *testing.T) {
+	hidden := &tui.Label{Text: "hidden"}
+	shown := &tui.Label{Text: "shown"}
+
+	c := &tui.Container{
+		Controls: []tui.Control{hidden, shown},
+		Layout: &tui.HBox{
+			Sizes: []tui.Size{tui.Fixed(0), tui.Fill},
+		},
+	}
+
+	s := tuitest.Run(t, c, 20, 1)
+
+	if *hidden.GetBounds() != (tui.Rect{Height: 1}) {
+		t.Errorf("expected no width, got %+v", *hidden.GetBounds())
+	}
+
+	if *shown.GetBounds() != (tui.Rect{Width: 20, Height: 1}) {
+		t.Errorf("expected the full width, got %+v", *shown.GetBounds())
+	}
+
+	tuitest.AssertLine(t, s, 0, "shown")
+}
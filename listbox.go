@@ -0,0 +1,348 @@
+package tui
+
+import (
+	"github.com/briansteffens/escapebox"
+	"github.com/nsf/termbox-go"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The items shown by a ListBox.
+type ListModel interface {
+	Len() int
+	Item(index int) string
+}
+
+// A ListModel holding a fixed list of strings.
+type StringList []string
+
+func (l StringList) Len() int {
+	return len(l)
+}
+
+func (l StringList) Item(index int) string {
+	return l[index]
+}
+
+// How many items of a ListBox can be selected at once.
+type SelectionMode int
+
+const (
+	// The item under the cursor is the selected one
+	SelectSingle SelectionMode = iota
+
+	// Any number of items can be selected, by pressing Space or clicking
+	// on them
+	SelectMulti
+)
+
+type ListSelectionChangedEvent func(l *ListBox)
+type ListItemActivatedEvent func(l *ListBox, index int)
+
+// Typing within this long of the previous key adds to the type-ahead search
+// rather than starting a new one.
+const typeAheadTimeout = time.Second
+
+// A ListBox shows a scrolling list of items to pick from. The cursor is moved
+// with the arrow keys, PgUp/PgDn and Home/End, or by typing the start of an
+// item. In SelectMulti mode each item has a check mark which Space toggles.
+// Enter activates the item under the cursor.
+type ListBox struct {
+	Invalidation
+
+	Bounds Rect
+	Items  ListModel
+	Mode   SelectionMode
+
+	// Called after items are selected or unselected
+	OnSelectionChanged ListSelectionChangedEvent
+
+	// Called when Enter is pressed
+	OnItemActivated ListItemActivatedEvent
+
+	cursor   int
+	scroll   int
+	focus    bool
+	selected map[int]bool
+
+	typeAhead   string
+	typeAheadAt time.Time
+}
+
+func (l *ListBox) GetBounds() *Rect {
+	return &l.Bounds
+}
+
+func (l *ListBox) SetFocus() {
+	l.focus = true
+	l.Invalidate()
+}
+
+func (l *ListBox) UnsetFocus() {
+	l.focus = false
+	l.Invalidate()
+}
+
+func (l *ListBox) count() int {
+	if l.Items == nil {
+		return 0
+	}
+
+	return l.Items.Len()
+}
+
+// The index of the item under the cursor, or -1 if there are no items.
+func (l *ListBox) Cursor() int {
+	if l.count() == 0 {
+		return -1
+	}
+
+	return l.cursor
+}
+
+// Move the cursor to the item at index and scroll it into view. In
+// SelectSingle mode this selects it too.
+func (l *ListBox) SetCursor(index int) {
+	previous := l.cursor
+
+	l.cursor = index
+	l.updateScroll()
+
+	if l.Mode == SelectSingle && l.cursor != previous {
+		l.selectionChanged()
+	}
+}
+
+// The indexes of the selected items, in order.
+func (l *ListBox) Selected() []int {
+	if l.Mode == SelectSingle {
+		if l.count() == 0 {
+			return []int{}
+		}
+
+		return []int{l.cursor}
+	}
+
+	ret := []int{}
+
+	for index, selected := range l.selected {
+		if selected && index < l.count() {
+			ret = append(ret, index)
+		}
+	}
+
+	sort.Ints(ret)
+
+	return ret
+}
+
+// Check whether the item at index is selected.
+func (l *ListBox) IsSelected(index int) bool {
+	if l.Mode == SelectSingle {
+		return l.count() > 0 && index == l.cursor
+	}
+
+	return l.selected[index]
+}
+
+// Select or unselect the item at index. In SelectSingle mode selecting an
+// item moves the cursor to it, and unselecting does nothing.
+func (l *ListBox) Select(index int, selected bool) {
+	if index < 0 || index >= l.count() ||
+		l.IsSelected(index) == selected {
+		return
+	}
+
+	if l.Mode == SelectSingle {
+		if selected {
+			l.SetCursor(index)
+		}
+
+		return
+	}
+
+	if l.selected == nil {
+		l.selected = map[int]bool{}
+	}
+
+	l.selected[index] = selected
+	l.Invalidate()
+	l.selectionChanged()
+}
+
+func (l *ListBox) selectionChanged() {
+	if l.OnSelectionChanged != nil {
+		l.OnSelectionChanged(l)
+	}
+}
+
+// Keep the cursor on an item and scroll it into view.
+func (l *ListBox) updateScroll() {
+	l.Invalidate()
+
+	// Clamp cursor
+	l.cursor = min(l.count()-1, l.cursor)
+	l.cursor = max(0, l.cursor)
+
+	// Clamp scroll
+	l.scroll = min(l.scroll, l.count()-l.Bounds.Height)
+	l.scroll = max(0, l.scroll)
+
+	if l.cursor < l.scroll {
+		l.scroll = l.cursor
+	}
+
+	if l.cursor >= l.scroll+l.Bounds.Height {
+		l.scroll = l.cursor - l.Bounds.Height + 1
+	}
+}
+
+// Move the cursor to the next item starting with the type-ahead text, adding
+// ch to it. A new search starts after the item under the cursor.
+func (l *ListBox) typeAheadJump(ch rune) {
+	now := time.Now()
+	start := l.cursor
+
+	if now.Sub(l.typeAheadAt) > typeAheadTimeout {
+		l.typeAhead = ""
+		start++
+	}
+
+	l.typeAhead += strings.ToLower(string(ch))
+	l.typeAheadAt = now
+
+	count := l.count()
+
+	for i := 0; i < count; i++ {
+		index := (start + i) % count
+		item := strings.ToLower(l.Items.Item(index))
+
+		if strings.HasPrefix(item, l.typeAhead) {
+			l.SetCursor(index)
+			return
+		}
+	}
+}
+
+func (l *ListBox) Draw(target *DrawTarget) {
+	l.updateScroll()
+
+	last := min(l.count(), l.scroll+l.Bounds.Height)
+
+	for index := l.scroll; index < last; index++ {
+		style := theme.Normal
+
+		if index == l.cursor {
+			style = theme.Header
+			if l.focus {
+				style = theme.Selected
+			}
+		}
+
+		text := l.Items.Item(index)
+
+		if l.Mode == SelectMulti {
+			check := " "
+			if l.selected[index] {
+				check = "X"
+			}
+
+			text = "[" + check + "] " + text
+		}
+
+		runes := []rune(text)
+		if len(runes) > l.Bounds.Width {
+			runes = runes[:l.Bounds.Width]
+		}
+
+		y := index - l.scroll
+
+		// Fill the row so the cursor shows across the whole width
+		for x := len(runes); x < l.Bounds.Width; x++ {
+			target.SetCell(x, y, style.Fg, style.Bg, ' ')
+		}
+
+		target.Print(0, y, style.Fg, style.Bg, "%s", string(runes))
+	}
+
+	if l.focus {
+		target.HideCursor()
+	}
+}
+
+func (l *ListBox) handleMouseEvent(ev escapebox.Event) bool {
+	if direction := wheelDirection(ev); direction != 0 {
+		l.scroll += direction * wheelLines
+		l.SetCursor(l.cursor + direction*wheelLines)
+		return true
+	}
+
+	if !leftClick(ev) {
+		return false
+	}
+
+	index := l.scroll + ev.MouseY
+	if index >= l.count() {
+		return false
+	}
+
+	l.SetCursor(index)
+
+	if l.Mode == SelectMulti {
+		l.Select(index, !l.selected[index])
+	}
+
+	return true
+}
+
+func (l *ListBox) HandleEvent(ev escapebox.Event) bool {
+	if ev.Type == termbox.EventMouse {
+		return l.handleMouseEvent(ev)
+	}
+
+	if ev.Type != termbox.EventKey || l.count() == 0 {
+		return false
+	}
+
+	cursor := l.cursor
+	handled := true
+
+	switch ev.Key {
+	case termbox.KeyArrowUp:
+		cursor--
+	case termbox.KeyArrowDown:
+		cursor++
+	case termbox.KeyPgup:
+		cursor -= max(1, l.Bounds.Height-1)
+	case termbox.KeyPgdn:
+		cursor += max(1, l.Bounds.Height-1)
+	case termbox.KeyHome:
+		cursor = 0
+	case termbox.KeyEnd:
+		cursor = l.count() - 1
+	case termbox.KeyEnter:
+		if l.OnItemActivated != nil {
+			l.OnItemActivated(l, l.cursor)
+		}
+	case termbox.KeySpace:
+		if l.Mode == SelectMulti {
+			l.Select(l.cursor, !l.selected[l.cursor])
+		} else {
+			l.typeAheadJump(' ')
+		}
+	default:
+		handled = false
+	}
+
+	if !handled && renderableChar(ev) {
+		l.typeAheadJump(ev.Ch)
+		return true
+	}
+
+	if cursor != l.cursor {
+		l.SetCursor(cursor)
+	}
+
+	return handled
+}
@@ -0,0 +1,63 @@
+package tui_test
+
+import (
+	"fmt"
+	"github.com/briansteffens/tui"
+	"github.com/briansteffens/tui/tuitest"
+	"github.com/nsf/termbox-go"
+	"testing"
+)
+
+func TestListBox(t *testing.T) {
+	databases := tui.StringList{"accounts", "billing", "inventory",
+		"orders", "users"}
+
+	changes := 0
+	activated := -1
+
+	list := &tui.ListBox{
+		Bounds: tui.Rect{Width: 12, Height: 3},
+		Items:  databases,
+		OnSelectionChanged: func(l *tui.ListBox) {
+			changes++
+		},
+		OnItemActivated: func(l *tui.ListBox, index int) {
+			activated = index
+		},
+	}
+
+	// Scroll down to orders, then jump back up to billing by typing
+	s := tuitest.Run(t, &tui.Container{Controls: []tui.Control{list}},
+		12, 3, tuitest.Key(termbox.KeyArrowDown),
+		tuitest.Key(termbox.KeyArrowDown),
+		tuitest.Key(termbox.KeyArrowDown), tuitest.Char('b'),
+		tuitest.Key(termbox.KeyEnter))
+
+	tuitest.AssertLine(t, s, 0, "billing")
+	tuitest.AssertLine(t, s, 2, "orders")
+
+	selected := tui.CurrentTheme().Selected
+	tuitest.AssertStyle(t, s, 11, 0, selected.Fg, selected.Bg)
+
+	if changes != 4 || activated != 1 {
+		t.Errorf("expected 4 changes and billing activated, got %d "+
+			"and %d", changes, activated)
+	}
+
+	list = &tui.ListBox{
+		Bounds: tui.Rect{Width: 16, Height: 3},
+		Items:  databases,
+		Mode:   tui.SelectMulti,
+	}
+
+	s = tuitest.Run(t, &tui.Container{Controls: []tui.Control{list}},
+		16, 3, tuitest.Key(termbox.KeySpace), tuitest.Key(termbox.KeyEnd),
+		tuitest.Key(termbox.KeySpace), tuitest.Key(termbox.KeyPgup))
+
+	tuitest.AssertLine(t, s, 0, "[ ] inventory")
+
+	if fmt.Sprint(list.Selected()) != "[0 4]" || list.Cursor() != 2 {
+		t.Errorf("unexpected selection %v and cursor %d",
+			list.Selected(), list.Cursor())
+	}
+}
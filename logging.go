@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Where the library's own logging goes. Disabled until SetLogHandler is
+// called.
+var logger = slog.New(discardHandler{})
+
+// Send the library's logging to h. Most of it is at slog.LevelDebug: focus
+// changes, unhandled key events, resizes, how long each Refresh took and
+// controls clipped by their parent. Controls with a negative size, which
+// can't be drawn at all, are logged at slog.LevelWarn. Pass nil to disable
+// logging again, which is the default.
+//
+// Since the terminal is in use by the UI, h will usually write to a file.
+func SetLogHandler(h slog.Handler) {
+	if h == nil {
+		h = discardHandler{}
+	}
+
+	logger = slog.New(h)
+}
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool {
+	return false
+}
+
+func (discardHandler) Handle(context.Context, slog.Record) error {
+	return nil
+}
+
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler {
+	return h
+}
+
+func (h discardHandler) WithGroup(string) slog.Handler {
+	return h
+}
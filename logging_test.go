@@ -0,0 +1,89 @@
+package tui_test
+
+import (
+	"context"
+	"github.com/briansteffens/tui"
+	"github.com/briansteffens/tui/tuitest"
+	"github.com/nsf/termbox-go"
+	"log/slog"
+	"testing"
+)
+
+// Records the messages logged at each level
+type capturingHandler struct {
+	messages map[slog.Level][]string
+}
+
+func (h *capturingHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *capturingHandler) Handle(_ context.Context, r slog.Record) error {
+	h.messages[r.Level] = append(h.messages[r.Level], r.Message)
+	return nil
+}
+
+func (h *capturingHandler) WithAttrs([]slog.Attr) slog.Handler {
+	return h
+}
+
+func (h *capturingHandler) WithGroup(string) slog.Handler {
+	return h
+}
+
+func (h *capturingHandler) logged(level slog.Level, message string) bool {
+	for _, m := range h.messages[level] {
+		if m == message {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestSetLogHandler(t *testing.T) {
+	h := &capturingHandler{messages: map[slog.Level][]string{}}
+
+	tui.SetLogHandler(h)
+	defer tui.SetLogHandler(nil)
+
+	c := &tui.Container{Controls: []tui.Control{
+		&tui.TextBox{Bounds: tui.Rect{Width: 20, Height: 3}},
+	}}
+
+	tuitest.Run(t, c, 20, 4, tuitest.Key(termbox.KeyF5),
+		tuitest.Resize(30, 5))
+
+	for _, message := range []string{"focus changed", "refreshed",
+		"unhandled key event", "resized"} {
+		if !h.logged(slog.LevelDebug, message) {
+			t.Errorf("expected %q to be logged, got %v", message,
+				h.messages)
+		}
+	}
+
+	// A control with a negative size can't be drawn at all
+	previous := tui.CurrentScreen()
+	tui.SetScreen(tuitest.NewScreen(20, 4))
+	defer tui.SetScreen(previous)
+
+	err := tui.MainLoop(&tui.Container{Controls: []tui.Control{
+		&tui.Label{Bounds: tui.Rect{Width: -1, Height: 1}},
+	}})
+
+	if err == nil ||
+		!h.logged(slog.LevelWarn, "child bounds have a negative size") {
+		t.Errorf("expected an error and a warning, got %v and %v", err,
+			h.messages)
+	}
+
+	// Nothing more is logged once the handler is removed
+	tui.SetLogHandler(nil)
+	count := len(h.messages[slog.LevelDebug])
+
+	tuitest.Run(t, c, 20, 4, tuitest.Key(termbox.KeyF5))
+
+	if len(h.messages[slog.LevelDebug]) != count {
+		t.Errorf("expected logging to stop, got %v", h.messages)
+	}
+}
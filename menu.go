@@ -0,0 +1,700 @@
+package tui
+
+import (
+	"github.com/briansteffens/escapebox"
+	"github.com/nsf/termbox-go"
+	"unicode"
+)
+
+type MenuSelectedEvent func(item *MenuItem)
+
+// An entry in a Menu: something to do, a check mark to toggle, a submenu, or
+// a separator line.
+type MenuItem struct {
+	// Shown in the menu. Put & before the letter which picks the item
+	// while its menu is open, like "Save &As"; otherwise it's the first
+	// letter. "&&" shows a single &.
+	Text string
+
+	// Called when the item is picked
+	OnSelected MenuSelectedEvent
+
+	// Picks the item from anywhere on the screen if it's in a MenuBar,
+	// or otherwise while its menu is open
+	KeyBinding KeyBinding
+
+	// Shown on the right of the item, like "Ctrl+S". Defaults to the name
+	// of KeyBinding.
+	Hint string
+
+	// Shown grayed out, and can't be picked
+	Disabled bool
+
+	// Checkable items show a check mark while Checked, and toggle it when
+	// they're picked
+	Checkable bool
+	Checked   bool
+
+	// Opened to the side when the item is picked
+	Submenu *Menu
+
+	// Draw a line between groups of items instead of an item. Nothing else
+	// is used.
+	Separator bool
+}
+
+func (i *MenuItem) hint() string {
+	if i.Hint != "" {
+		return i.Hint
+	}
+
+	return i.KeyBinding.String()
+}
+
+// Toggle the check mark and call OnSelected.
+func (i *MenuItem) pick() {
+	if i.Checkable {
+		i.Checked = !i.Checked
+	}
+
+	if i.OnSelected != nil {
+		i.OnSelected(i)
+	}
+}
+
+// A list of MenuItems, dropped down from a MenuBar, opened to the side as a
+// submenu, or shown anywhere with ShowContextMenu.
+type Menu struct {
+	// Shown in a MenuBar, marked with & like MenuItem.Text. Alt and the
+	// marked letter open the menu.
+	Title string
+
+	Items []*MenuItem
+}
+
+// Split text marked with & into the text to show and the position of its
+// mnemonic: the marked letter, or the first one if none is marked. The
+// position is -1 if the text is empty.
+func parseMnemonic(text string) ([]rune, int) {
+	runes := []rune(text)
+	ret := []rune{}
+	mnemonic := -1
+
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '&' && i+1 < len(runes) {
+			i++
+
+			if runes[i] != '&' && mnemonic < 0 {
+				mnemonic = len(ret)
+			}
+		}
+
+		ret = append(ret, runes[i])
+	}
+
+	if mnemonic < 0 && len(ret) > 0 {
+		mnemonic = 0
+	}
+
+	return ret, mnemonic
+}
+
+// Check whether the mnemonic of text is ch, ignoring case.
+func hasMnemonic(text string, ch rune) bool {
+	runes, mnemonic := parseMnemonic(text)
+
+	return mnemonic >= 0 &&
+		unicode.ToLower(runes[mnemonic]) == unicode.ToLower(ch)
+}
+
+// Get the letter of an Alt+letter event.
+func altLetter(ev escapebox.Event) (rune, bool) {
+	seq := int(ev.Seq)
+
+	if ev.Type != termbox.EventKey || seq < SeqAltA || seq > SeqAltZ {
+		return 0, false
+	}
+
+	return rune('a' + seq - SeqAltA), true
+}
+
+// Find an item which can be picked with the key in ev, looking inside
+// submenus too.
+func findAccelerator(items []*MenuItem, ev escapebox.Event) *MenuItem {
+	for _, item := range items {
+		if item.Disabled || item.Separator {
+			continue
+		}
+
+		if matchBinding(ev, item.KeyBinding) {
+			return item
+		}
+
+		if item.Submenu != nil {
+			found := findAccelerator(item.Submenu.Items, ev)
+			if found != nil {
+				return found
+			}
+		}
+	}
+
+	return nil
+}
+
+// A MenuBar shows the titles of its Menus in a row, usually along the top of
+// the screen, and drops a menu down when its title is clicked. The open
+// binding opens the first menu and Alt and a title's letter open that one.
+// Then the arrow keys move through the items and across to the other menus,
+// Enter picks an item, a letter picks the item it marks, and Esc closes the
+// menu.
+//
+// The KeyBindings of the items work anywhere on the screen while the MenuBar
+// is in the root Container, as long as no Dialog is open and the focused
+// control doesn't use the key itself.
+type MenuBar struct {
+	Invalidation
+
+	Bounds Rect
+	Menus  []*Menu
+
+	// Opens the first menu. Zero means F10.
+	KeyBindingOpen KeyBinding
+
+	// The menu which is dropped down, if any
+	open *Menu
+}
+
+func (b *MenuBar) GetBounds() *Rect {
+	return &b.Bounds
+}
+
+func (b *MenuBar) keyBindingOpen() KeyBinding {
+	if b.KeyBindingOpen == (KeyBinding{}) {
+		return KeyBinding{Key: termbox.KeyF10}
+	}
+
+	return b.KeyBindingOpen
+}
+
+// The x position of each title, followed by where the last one ends. Titles
+// have a space on either side.
+func (b *MenuBar) titleLefts() []int {
+	ret := []int{0}
+
+	for _, m := range b.Menus {
+		title, _ := parseMnemonic(m.Title)
+		ret = append(ret, ret[len(ret)-1]+len(title)+2)
+	}
+
+	return ret
+}
+
+// The index of the menu whose title is at x, or -1.
+func (b *MenuBar) titleAt(x int) int {
+	lefts := b.titleLefts()
+
+	for i := range b.Menus {
+		if x >= lefts[i] && x < lefts[i+1] {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// The index of the menu whose title has the mnemonic ch, or -1.
+func (b *MenuBar) menuFor(ch rune) int {
+	for i, m := range b.Menus {
+		if hasMnemonic(m.Title, ch) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (b *MenuBar) Draw(target *DrawTarget) {
+	style := theme.Menu
+
+	for x := 0; x < b.Bounds.Width; x++ {
+		target.SetCell(x, 0, style.Fg, style.Bg, ' ')
+	}
+
+	lefts := b.titleLefts()
+
+	for i, m := range b.Menus {
+		style := theme.Menu
+		if m == b.open {
+			style = theme.Selected
+		}
+
+		title, mnemonic := parseMnemonic(m.Title)
+
+		target.SetCell(lefts[i], 0, style.Fg, style.Bg, ' ')
+		target.SetCell(lefts[i+1]-1, 0, style.Fg, style.Bg, ' ')
+
+		for x, ch := range title {
+			fg := style.Fg
+			if x == mnemonic {
+				fg |= AttrUnderline
+			}
+
+			target.SetCell(lefts[i]+1+x, 0, fg, style.Bg, ch)
+		}
+	}
+}
+
+// The MenuBars in the Container.
+func (c *Container) menuBars() []*MenuBar {
+	ret := []*MenuBar{}
+
+	for _, ctrl := range leaves(c.Controls) {
+		if b, ok := ctrl.(*MenuBar); ok {
+			ret = append(ret, b)
+		}
+	}
+
+	return ret
+}
+
+// Drop down the menu of b at index, closing any other open menus.
+func (c *Container) openMenuBar(b *MenuBar, index int) {
+	root := c.root()
+
+	bounds, ok := root.screenBounds(b)
+	if !ok {
+		return
+	}
+
+	s := root.startMenu(b)
+
+	b.open = b.Menus[index]
+	b.Invalidate()
+
+	left := bounds.Left + b.titleLefts()[index]
+	s.push(b.open, left, bounds.Top+1, -1)
+}
+
+// Open m as a context menu at (x, y) in ctrl's coordinates, or in screen
+// coordinates if ctrl is nil, closing any other open menus. The menu closes
+// when an item is picked, Esc is pressed or the mouse is clicked outside it.
+func (c *Container) ShowContextMenu(m *Menu, ctrl Control, x, y int) {
+	root := c.root()
+
+	if ctrl != nil {
+		bounds, ok := root.screenBounds(ctrl)
+		if !ok {
+			return
+		}
+
+		x += bounds.Left
+		y += bounds.Top
+	}
+
+	root.startMenu(nil).push(m, x, y, -1)
+}
+
+// Close any open menus, and start a new set for bar, or for a context menu
+// if bar is nil.
+func (c *Container) startMenu(bar *MenuBar) *menuSession {
+	if c.menu != nil {
+		c.menu.close()
+	}
+
+	c.menu = &menuSession{root: c, bar: bar}
+
+	return c.menu
+}
+
+// Open a MenuBar's menu with its bindings, or pick an item with its
+// KeyBinding.
+func (c *Container) handleMenuKey(ev escapebox.Event) bool {
+	if ev.Type != termbox.EventKey {
+		return false
+	}
+
+	bars := c.menuBars()
+
+	for _, b := range bars {
+		if matchBinding(ev, b.keyBindingOpen()) && len(b.Menus) > 0 {
+			c.openMenuBar(b, 0)
+			return true
+		}
+
+		if ch, ok := altLetter(ev); ok {
+			if index := b.menuFor(ch); index >= 0 {
+				c.openMenuBar(b, index)
+				return true
+			}
+		}
+	}
+
+	for _, b := range bars {
+		for _, m := range b.Menus {
+			if item := findAccelerator(m.Items, ev); item != nil {
+				item.pick()
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Open a MenuBar's menu when its title is clicked, and close the open menus
+// when the mouse is clicked anywhere outside them. Returns whether the click
+// was used.
+func (c *Container) handleMenuMouse(ev escapebox.Event) bool {
+	if !leftClick(ev) {
+		return false
+	}
+
+	x, y := ev.MouseX, ev.MouseY
+
+	if c.topDialog() == nil && c.windowAt(x, y) == nil {
+		for _, b := range c.menuBars() {
+			bounds, ok := c.screenBounds(b)
+			if !ok || !bounds.ContainsPoint(x, y) {
+				continue
+			}
+
+			index := b.titleAt(x - bounds.Left)
+
+			// Clicking the open menu's title closes it
+			if index >= 0 && (c.menu == nil ||
+				b.open != b.Menus[index]) {
+				c.openMenuBar(b, index)
+			} else if c.menu != nil {
+				c.menu.close()
+			}
+
+			return true
+		}
+	}
+
+	if c.menu != nil {
+		c.menu.close()
+		return true
+	}
+
+	return false
+}
+
+// The menus open on the screen: a MenuBar's menu or a context menu, followed
+// by the submenus opened from it
+type menuSession struct {
+	root *Container
+
+	// The MenuBar the first menu belongs to, or nil for a context menu
+	bar *MenuBar
+
+	open []*menuPopup
+}
+
+// Open m with its top-left corner at (x, y), or with its right edge at
+// rightEdge if it would go off the right of the screen. A negative rightEdge
+// means the right edge of the screen.
+func (s *menuSession) push(m *Menu, x, y, rightEdge int) {
+	p := &menuPopup{menu: m, session: s, cursor: -1}
+	p.move(1)
+
+	width, height := p.size()
+	screenWidth, screenHeight := s.root.Bounds.Width, s.root.Bounds.Height
+
+	if rightEdge < 0 {
+		rightEdge = screenWidth
+	}
+
+	if x+width > screenWidth {
+		x = rightEdge - width
+	}
+
+	if y+height > screenHeight {
+		y = screenHeight - height
+	}
+
+	p.Bounds = Rect{max(0, x), max(0, y), width, height}
+
+	s.open = append(s.open, p)
+	s.root.showPopup(p)
+}
+
+// The menu the keyboard goes to.
+func (s *menuSession) top() *menuPopup {
+	return s.open[len(s.open)-1]
+}
+
+// Close the top menu, or all of them if it's the last.
+func (s *menuSession) pop() {
+	if len(s.open) <= 1 {
+		s.close()
+		return
+	}
+
+	s.root.hidePopup(s.top())
+	s.open = s.open[:len(s.open)-1]
+}
+
+// Close all the menus.
+func (s *menuSession) close() {
+	for _, p := range s.open {
+		s.root.hidePopup(p)
+	}
+
+	s.open = nil
+
+	if s.bar != nil {
+		s.bar.open = nil
+		s.bar.Invalidate()
+	}
+
+	if s.root.menu == s {
+		s.root.menu = nil
+	}
+}
+
+// Pick the item of p at index: open its submenu, or close the menus and run
+// it.
+func (s *menuSession) pick(p *menuPopup, index int) {
+	if index < 0 || index >= len(p.menu.Items) {
+		return
+	}
+
+	item := p.menu.Items[index]
+	if item.Separator || item.Disabled {
+		return
+	}
+
+	p.cursor = index
+	p.Invalidate()
+
+	if item.Submenu != nil {
+		s.openSubmenu(p)
+		return
+	}
+
+	s.close()
+	item.pick()
+}
+
+// Open the submenu of the highlighted item of p beside it, closing any menus
+// opened after p.
+func (s *menuSession) openSubmenu(p *menuPopup) {
+	for s.top() != p {
+		s.pop()
+	}
+
+	item := p.menu.Items[p.cursor]
+
+	// Line the submenu's first item up with the item, opening it to the
+	// left instead if there's no room on the right
+	s.push(item.Submenu, p.Bounds.Right()+1, p.Bounds.Top+p.cursor,
+		p.Bounds.Left)
+}
+
+// Drop down the MenuBar's menu step places along from the open one.
+func (s *menuSession) switchMenu(step int) {
+	b := s.bar
+	count := len(b.Menus)
+
+	for i, m := range b.Menus {
+		if m == b.open {
+			s.root.openMenuBar(b, (i+step+count)%count)
+			return
+		}
+	}
+}
+
+// Move through the open menus with the arrow keys, pick items with Enter or
+// their letters, and close menus with Esc. The menus take over the keyboard
+// while they're open.
+func (s *menuSession) handleKey(ev escapebox.Event) bool {
+	if ev.Type != termbox.EventKey {
+		return false
+	}
+
+	p := s.top()
+
+	var highlighted *MenuItem
+	if p.cursor >= 0 {
+		highlighted = p.menu.Items[p.cursor]
+	}
+
+	ch, alt := altLetter(ev)
+
+	switch {
+	case ev.Key == termbox.KeyArrowUp:
+		p.move(-1)
+	case ev.Key == termbox.KeyArrowDown:
+		p.move(1)
+	case ev.Key == termbox.KeyArrowRight:
+		if highlighted != nil && highlighted.Submenu != nil &&
+			!highlighted.Disabled {
+			s.openSubmenu(p)
+		} else if s.bar != nil {
+			s.switchMenu(1)
+		}
+	case ev.Key == termbox.KeyArrowLeft:
+		if len(s.open) > 1 {
+			s.pop()
+		} else if s.bar != nil {
+			s.switchMenu(-1)
+		}
+	case ev.Key == termbox.KeyEnter || ev.Key == termbox.KeySpace:
+		s.pick(p, p.cursor)
+	case ev.Key == termbox.KeyEsc:
+		s.pop()
+	case s.bar != nil && matchBinding(ev, s.bar.keyBindingOpen()):
+		s.close()
+	case s.bar != nil && alt && s.bar.menuFor(ch) >= 0:
+		s.root.openMenuBar(s.bar, s.bar.menuFor(ch))
+	case renderableChar(ev):
+		s.pick(p, p.itemFor(ev.Ch))
+	default:
+		if item := findAccelerator(p.menu.Items, ev); item != nil {
+			s.close()
+			item.pick()
+		}
+	}
+
+	return true
+}
+
+// An open menu, shown as a popup.
+type menuPopup struct {
+	Invalidation
+
+	Bounds  Rect
+	menu    *Menu
+	session *menuSession
+
+	// The highlighted item, or -1 if there are only separators
+	cursor int
+}
+
+func (p *menuPopup) GetBounds() *Rect {
+	return &p.Bounds
+}
+
+// The widest item text and hint.
+func (p *menuPopup) columns() (int, int) {
+	textWidth, hintWidth := 0, 0
+
+	for _, item := range p.menu.Items {
+		text, _ := parseMnemonic(item.Text)
+		textWidth = max(textWidth, len(text))
+		hintWidth = max(hintWidth, len([]rune(item.hint())))
+	}
+
+	return textWidth, hintWidth
+}
+
+// The size of the menu, including its border. Each item has room for a check
+// mark on the left and a submenu arrow on the right.
+func (p *menuPopup) size() (int, int) {
+	textWidth, hintWidth := p.columns()
+
+	width := textWidth + 8
+	if hintWidth > 0 {
+		width += hintWidth + 2
+	}
+
+	return width, len(p.menu.Items) + 2
+}
+
+// Move the highlight step items along, skipping separators and wrapping
+// around at the ends.
+func (p *menuPopup) move(step int) {
+	count := len(p.menu.Items)
+
+	for i := 1; i <= count; i++ {
+		index := ((p.cursor+step*i)%count + count) % count
+
+		if !p.menu.Items[index].Separator {
+			p.cursor = index
+			p.Invalidate()
+			return
+		}
+	}
+}
+
+// The index of the item whose mnemonic is ch, or -1.
+func (p *menuPopup) itemFor(ch rune) int {
+	for i, item := range p.menu.Items {
+		if !item.Separator && !item.Disabled &&
+			hasMnemonic(item.Text, ch) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (p *menuPopup) Draw(target *DrawTarget) {
+	target.Border(BorderSingle, theme.Menu, "")
+
+	width := p.Bounds.Width
+
+	for i, item := range p.menu.Items {
+		y := i + 1
+
+		if item.Separator {
+			fg, bg := theme.Menu.Fg, theme.Menu.Bg
+
+			target.SetCell(0, y, fg, bg, '├')
+			for x := 1; x < width-1; x++ {
+				target.SetCell(x, y, fg, bg, '─')
+			}
+			target.SetCell(width-1, y, fg, bg, '┤')
+
+			continue
+		}
+
+		style := theme.Menu
+		if i == p.cursor {
+			style = theme.Selected
+		}
+
+		if item.Disabled {
+			style.Fg = theme.Disabled.Fg
+		}
+
+		for x := 1; x < width-1; x++ {
+			target.SetCell(x, y, style.Fg, style.Bg, ' ')
+		}
+
+		if item.Checkable && item.Checked {
+			target.SetCell(2, y, style.Fg, style.Bg, '✓')
+		}
+
+		text, mnemonic := parseMnemonic(item.Text)
+
+		for x, ch := range text {
+			fg := style.Fg
+			if x == mnemonic && !item.Disabled {
+				fg |= AttrUnderline
+			}
+
+			target.SetCell(4+x, y, fg, style.Bg, ch)
+		}
+
+		hint := []rune(item.hint())
+		target.Print(width-3-len(hint), y, style.Fg, style.Bg, "%s",
+			string(hint))
+
+		if item.Submenu != nil {
+			target.SetCell(width-3, y, style.Fg, style.Bg, '▸')
+		}
+	}
+}
+
+// Pick an item by clicking on it.
+func (p *menuPopup) HandleEvent(ev escapebox.Event) bool {
+	if !leftClick(ev) || ev.MouseX < 1 || ev.MouseX >= p.Bounds.Width-1 {
+		return false
+	}
+
+	p.session.pick(p, ev.MouseY-1)
+
+	return true
+}
@@ -0,0 +1,131 @@
+package tui_test
+
+import (
+	"fmt"
+	"github.com/briansteffens/escapebox"
+	"github.com/briansteffens/tui"
+	"github.com/briansteffens/tui/tuitest"
+	"github.com/nsf/termbox-go"
+	"testing"
+)
+
+func TestMenuBar(t *testing.T) {
+	picked := []string{}
+	record := func(item *tui.MenuItem) {
+		picked = append(picked, item.Text)
+	}
+
+	wrap := &tui.MenuItem{Text: "&Word wrap", Checkable: true}
+
+	bar := &tui.MenuBar{
+		Bounds: tui.Rect{Width: 30, Height: 1},
+		Menus: []*tui.Menu{
+			{Title: "&File", Items: []*tui.MenuItem{
+				{Text: "&New", OnSelected: record,
+					KeyBinding: tui.KeyBinding{
+						Key: termbox.KeyCtrlN}},
+				{Separator: true},
+				{Text: "&Recent", Submenu: &tui.Menu{
+					Items: []*tui.MenuItem{
+						{Text: "a.txt", OnSelected: record},
+						{Text: "b.txt", OnSelected: record},
+					}}},
+				{Text: "&Print", Disabled: true, OnSelected: record},
+				wrap,
+			}},
+			{Title: "&Edit", Items: []*tui.MenuItem{
+				{Text: "&Undo", OnSelected: record},
+			}},
+		},
+	}
+
+	// Focus starts on the second control
+	c := &tui.Container{
+		Controls: []tui.Control{
+			&tui.Label{Bounds: tui.Rect{Top: 1, Width: 30, Height: 1}},
+			bar,
+			&tui.TextBox{Bounds: tui.Rect{Top: 2, Width: 30, Height: 3}},
+		},
+	}
+
+	// F10 drops the first menu down, and the submenu opens to the right
+	s := tuitest.Run(t, c, 40, 10, tuitest.Key(termbox.KeyF10),
+		tuitest.Key(termbox.KeyArrowDown),
+		tuitest.Key(termbox.KeyArrowRight))
+
+	tuitest.AssertLine(t, s, 0, " File  Edit")
+	tuitest.AssertLine(t, s, 2, "│   New         Ctrl+N  │")
+	tuitest.AssertLine(t, s, 3, "├───────────────────────┤┌───────────┐")
+	tuitest.AssertLine(t, s, 4, "│   Recent            ▸ ││   a.txt   │")
+	tuitest.AssertLine(t, s, 5, "│   Print               ││   b.txt   │")
+
+	selected := tui.CurrentTheme().Selected
+	tuitest.AssertStyle(t, s, 2, 0, selected.Fg, selected.Bg)
+	tuitest.AssertStyle(t, s, 28, 4, selected.Fg, selected.Bg)
+
+	// Enter picks the item and closes the menus
+	s = tuitest.Run(t, c, 40, 10, tuitest.Key(termbox.KeyEnter))
+
+	tuitest.AssertLine(t, s, 2, "")
+
+	// Alt and a title's letter open that menu, and a letter picks an
+	// item. Disabled items can't be picked, and Left and Right move
+	// across the menus.
+	s = tuitest.Run(t, c, 40, 10, tuitest.Seq(tui.SeqAltA+'f'-'a'),
+		tuitest.Char('w'), tuitest.Seq(tui.SeqAltA+'f'-'a'),
+		tuitest.Char('p'), tuitest.Key(termbox.KeyArrowLeft))
+
+	tuitest.AssertLine(t, s, 1, "      ┌──────────┐")
+	tuitest.AssertLine(t, s, 2, "      │   Undo   │")
+
+	if !wrap.Checked {
+		t.Error("expected the check item to be checked")
+	}
+
+	// Items' KeyBindings work anywhere, and clicking a title opens its
+	// menu
+	s = tuitest.Run(t, c, 40, 10, tuitest.Key(termbox.KeyEsc),
+		tuitest.Key(termbox.KeyCtrlN), tuitest.Click(7, 0),
+		tuitest.Click(8, 2))
+
+	tuitest.AssertLine(t, s, 2, "")
+
+	if fmt.Sprint(picked) != "[a.txt &New &Undo]" {
+		t.Errorf("expected a.txt, New and Undo to be picked, got %v",
+			picked)
+	}
+
+	// Any control can open a context menu, which closes when the mouse
+	// is clicked outside it
+	menu := &tui.Menu{Items: []*tui.MenuItem{
+		{Text: "Cu&t", OnSelected: record},
+		{Text: "&Copy", OnSelected: record},
+	}}
+
+	c.EventHandler = func(c *tui.Container, ev escapebox.Event) bool {
+		if ev.Type != termbox.EventMouse || ev.Key != termbox.MouseRight {
+			return false
+		}
+
+		c.ShowContextMenu(menu, nil, ev.MouseX, ev.MouseY)
+		return true
+	}
+
+	s = tuitest.Run(t, c, 40, 10,
+		tuitest.Mouse(termbox.MouseRight, 36, 3))
+
+	// It moves left to fit on the screen
+	tuitest.AssertLine(t, s, 3, "                            ┌──────────┐")
+	tuitest.AssertLine(t, s, 4, "                            │   Cut    │")
+
+	s = tuitest.Run(t, c, 40, 10,
+		tuitest.Mouse(termbox.MouseRight, 36, 3),
+		tuitest.Click(0, 9), tuitest.Mouse(termbox.MouseRight, 2, 5),
+		tuitest.Char('t'))
+
+	tuitest.AssertLine(t, s, 5, "")
+
+	if picked[len(picked)-1] != "Cu&t" || len(picked) != 4 {
+		t.Errorf("expected only Cut to be picked, got %v", picked)
+	}
+}
@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"github.com/briansteffens/escapebox"
+)
+
+// A control shown above everything else on the screen, like the list of an
+// open ComboBox. Its Bounds are in screen coordinates. It gets the mouse
+// events over it but never has focus: the control which opened it handles the
+// keyboard.
+type popup interface {
+	Control
+	HandleEvent(ev escapebox.Event) bool
+}
+
+// Controls which need to know the Container they're in, like ComboBox to open
+// its popup
+type placed interface {
+	placedIn(c *Container)
+}
+
+// Show p above everything else.
+func (c *Container) showPopup(p popup) {
+	root := c.root()
+
+	for _, open := range root.popups {
+		if open == p {
+			return
+		}
+	}
+
+	root.popups = append(root.popups, p)
+}
+
+// Remove p from the screen.
+func (c *Container) hidePopup(p popup) {
+	root := c.root()
+
+	for i, open := range root.popups {
+		if open == p {
+			root.popups = append(root.popups[:i], root.popups[i+1:]...)
+
+			// Uncover what was underneath
+			root.Invalidate()
+			return
+		}
+	}
+}
+
+// Find where ctrl is on the screen, looking inside open Windows and Dialogs
+// too.
+func (c *Container) screenBounds(ctrl Control) (Rect, bool) {
+	root := c.root()
+
+	controls := append(append([]Control{}, root.Controls...),
+		root.layers()...)
+
+	return findBounds(controls, ctrl)
+}
+
+// The topmost popup at (x, y), if any.
+func (c *Container) popupAt(x, y int) popup {
+	for i := len(c.popups) - 1; i >= 0; i-- {
+		if c.popups[i].GetBounds().ContainsPoint(x, y) {
+			return c.popups[i]
+		}
+	}
+
+	return nil
+}
+
+// Send a mouse event to a popup, in its local coordinates.
+func handlePopupMouse(p popup, ev escapebox.Event) bool {
+	local := ev
+	local.MouseX -= p.GetBounds().Left
+	local.MouseY -= p.GetBounds().Top
+
+	p.HandleEvent(local)
+
+	// Whatever is underneath is covered
+	return true
+}
+
+// Draw the open popups on top of everything else. If anything underneath was
+// redrawn, every popup is too.
+func (c *Container) drawPopups(target *DrawTarget, redrawAll bool) {
+	for _, p := range c.popups {
+		if !redrawAll && !needsDraw(p) {
+			continue
+		}
+
+		popupTarget, err := target.Slice(p.GetBounds())
+		if err != nil {
+			target.ReportError(err)
+			continue
+		}
+
+		popupTarget.Clear()
+		p.Draw(popupTarget)
+		validate(p)
+
+		// Anything above overlaps this one
+		redrawAll = true
+	}
+}
@@ -0,0 +1,118 @@
+package tui
+
+import (
+	"github.com/nsf/termbox-go"
+	"sync"
+)
+
+// Functions waiting to be run on the UI goroutine by MainLoop
+var posted struct {
+	sync.Mutex
+	funcs []func()
+
+	// Tells the running MainLoop's waker that funcs were queued, or nil
+	// if MainLoop isn't running
+	wake chan struct{}
+
+	// Whether the waker is in the middle of interrupting the screen
+	interrupting bool
+}
+
+// Queue f to be run on the UI goroutine (the one running MainLoop), after
+// which the screen is refreshed. Post is safe to call from any goroutine.
+// Functions posted while MainLoop isn't running are run when it next starts.
+// The refresh only redraws invalidated controls, so f must call Invalidate on
+// any control whose fields it changes.
+//
+// Controls and Containers are not safe for concurrent use: once MainLoop is
+// running, they must only be read or modified on the UI goroutine, from event
+// handlers or functions passed to Post. For example, a goroutine running a
+// database query should hand its results to a DetailView like this:
+//
+//	tui.Post(func() {
+//		dv.Rows = rows
+//		dv.Invalidate()
+//	})
+func Post(f func()) {
+	posted.Lock()
+	defer posted.Unlock()
+
+	posted.funcs = append(posted.funcs, f)
+
+	// MainLoop only needs waking for the first function it hasn't run yet
+	if len(posted.funcs) > 1 || posted.wake == nil {
+		return
+	}
+
+	select {
+	case posted.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Interrupt s whenever a function is posted, so MainLoop wakes up to run it,
+// until the returned function is called. Interrupting blocks until MainLoop
+// polls for events, so it's done on a goroutine of its own rather than in
+// Post, which might be called from the UI goroutine itself.
+func startWaker(s Screen) func() {
+	wake := make(chan struct{}, 1)
+	done := make(chan struct{})
+
+	posted.Lock()
+	posted.wake = wake
+	posted.Unlock()
+
+	go func() {
+		for {
+			select {
+			case <-wake:
+			case <-done:
+				return
+			}
+
+			posted.Lock()
+			select {
+			case <-done:
+				posted.Unlock()
+				return
+			default:
+			}
+			posted.interrupting = true
+			posted.Unlock()
+
+			s.Interrupt()
+
+			posted.Lock()
+			posted.interrupting = false
+			posted.Unlock()
+		}
+	}()
+
+	return func() {
+		posted.Lock()
+		close(done)
+		posted.wake = nil
+		pending := posted.interrupting
+		posted.Unlock()
+
+		// Take the interrupt the waker is sending, so it doesn't wait
+		// forever for a MainLoop which has stopped polling
+		for pending {
+			ev := s.PollEvent()
+			pending = ev.Type != termbox.EventInterrupt &&
+				ev.Type != termbox.EventError
+		}
+	}
+}
+
+// Run everything queued by Post. Called by MainLoop on the UI goroutine.
+func runPosted() {
+	posted.Lock()
+	funcs := posted.funcs
+	posted.funcs = nil
+	posted.Unlock()
+
+	for _, f := range funcs {
+		f()
+	}
+}
@@ -0,0 +1,100 @@
+package tui_test
+
+import (
+	"github.com/briansteffens/escapebox"
+	"github.com/briansteffens/tui"
+	"github.com/briansteffens/tui/tuitest"
+	"strings"
+	"testing"
+)
+
+func TestPost(t *testing.T) {
+	label := &tui.Label{Bounds: tui.Rect{Width: 20, Height: 1},
+		Text: "waiting"}
+
+	ran := 0
+	posted := make(chan bool)
+
+	// Post from another goroutine, like a finished database query
+	c := &tui.Container{
+		Controls: []tui.Control{label},
+		EventHandler: func(c *tui.Container, ev escapebox.Event) bool {
+			go func() {
+				tui.Post(func() {
+					ran++
+					label.Text = "done"
+					label.Invalidate()
+				})
+				tui.Post(func() {
+					ran++
+				})
+				posted <- true
+			}()
+
+			<-posted
+			return true
+		},
+	}
+
+	s := tuitest.Run(t, c, 20, 1, tuitest.Char('p'),
+		tuitest.WaitForInterrupt())
+
+	tuitest.AssertLine(t, s, 0, "done")
+
+	// One refresh at the start and one after the posted functions ran
+	if ran != 2 || s.Flushes() != 2 {
+		t.Errorf("expected both functions to run before a refresh, "+
+			"got %d runs and %d flushes", ran, s.Flushes())
+	}
+}
+
+// The example from Post's documentation, handing query results to a
+// DetailView
+func TestPostDetailViewRows(t *testing.T) {
+	dv := &tui.DetailView{
+		Bounds:  tui.Rect{Width: 20, Height: 4},
+		Columns: []tui.Column{{Name: "Name", Width: 10}},
+	}
+
+	posted := make(chan bool)
+
+	c := &tui.Container{
+		Controls: []tui.Control{dv},
+		EventHandler: func(c *tui.Container, ev escapebox.Event) bool {
+			go func() {
+				rows := [][]string{{"alice"}, {"bob"}}
+
+				tui.Post(func() {
+					dv.Rows = rows
+					dv.Invalidate()
+				})
+				posted <- true
+			}()
+
+			<-posted
+			return true
+		},
+	}
+
+	s := tuitest.Run(t, c, 20, 4, tuitest.Char('q'),
+		tuitest.WaitForInterrupt())
+
+	if !strings.Contains(s.Text(), "alice") ||
+		!strings.Contains(s.Text(), "bob") {
+		t.Errorf("expected the posted rows on screen, got:\n%s",
+			s.Text())
+	}
+}
+
+func TestPostBeforeMainLoop(t *testing.T) {
+	label := &tui.Label{Bounds: tui.Rect{Width: 20, Height: 1}}
+
+	tui.Post(func() {
+		label.Text = "posted early"
+	})
+
+	s := tuitest.Run(t, &tui.Container{Controls: []tui.Control{label}},
+		20, 1)
+
+	tuitest.AssertLine(t, s, 0, "posted early")
+}
@@ -0,0 +1,236 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// How often ProgressBars and Spinners animate, unless Interval is set
+const defaultAnimationInterval = 100 * time.Millisecond
+
+// Blocks filling a cell from the left in eighths, from empty to full
+var eighths = []rune(" ▏▎▍▌▋▊▉█")
+
+// Something which redraws itself on a Timer, like a Spinner.
+type animation struct {
+	timer *Timer
+	frame int
+}
+
+// Start advancing the frame every interval, calling invalidate each time.
+func (a *animation) start(interval time.Duration, invalidate func()) {
+	if a.timer != nil {
+		return
+	}
+
+	if interval <= 0 {
+		interval = defaultAnimationInterval
+	}
+
+	a.timer = Every(interval, func() {
+		a.frame++
+		invalidate()
+	})
+}
+
+func (a *animation) stop() {
+	if a.timer != nil {
+		a.timer.Stop()
+		a.timer = nil
+	}
+}
+
+// A ProgressBar shows how far along something is, filling smoothly from the
+// left with eighth-cell blocks. Label and the percentage are shown in the
+// middle of the bar.
+//
+// When Indeterminate is set, a block bounces back and forth instead, for
+// operations which don't know how long they'll take. It animates on its own
+// while it's drawn; call Stop when it's taken off the screen for good.
+type ProgressBar struct {
+	Invalidation
+
+	Bounds Rect
+
+	// How far along, from 0 to 1
+	Progress float64
+
+	// Shown before the percentage, like "Loading"
+	Label string
+
+	HidePercentage bool
+	Indeterminate  bool
+
+	// How often the indeterminate block moves. Zero means every 100ms.
+	Interval time.Duration
+
+	animation animation
+}
+
+func (p *ProgressBar) GetBounds() *Rect {
+	return &p.Bounds
+}
+
+// Set Progress, from 0 to 1.
+func (p *ProgressBar) SetProgress(progress float64) {
+	progress = math.Max(0, math.Min(progress, 1))
+
+	if progress != p.Progress {
+		p.Progress = progress
+		p.Invalidate()
+	}
+}
+
+// Stop animating an indeterminate ProgressBar.
+func (p *ProgressBar) Stop() {
+	p.animation.stop()
+}
+
+// The text shown in the middle of the bar.
+func (p *ProgressBar) text() string {
+	if p.Indeterminate || p.HidePercentage {
+		return p.Label
+	}
+
+	percentage := fmt.Sprintf("%d%%", int(p.Progress*100))
+
+	if p.Label == "" {
+		return percentage
+	}
+
+	return p.Label + " " + percentage
+}
+
+// How many eighths of a cell are filled in each cell of the bar.
+func (p *ProgressBar) fill() []int {
+	width := p.Bounds.Width
+	ret := make([]int, width)
+
+	if p.Indeterminate {
+		size := max(1, width/5)
+		travel := max(1, width-size)
+
+		// Bounce back and forth across the bar
+		position := p.animation.frame % (2 * travel)
+		if position > travel {
+			position = 2*travel - position
+		}
+
+		for x := position; x < min(width, position+size); x++ {
+			ret[x] = 8
+		}
+
+		return ret
+	}
+
+	filled := int(math.Round(p.Progress * float64(width*8)))
+
+	for x := range ret {
+		ret[x] = max(0, min(8, filled-x*8))
+	}
+
+	return ret
+}
+
+func (p *ProgressBar) Draw(target *DrawTarget) {
+	if p.Indeterminate {
+		p.animation.start(p.Interval, p.Invalidate)
+	} else {
+		p.animation.stop()
+	}
+
+	fill := p.fill()
+	text := []rune(p.text())
+	textLeft := (p.Bounds.Width - len(text)) / 2
+	row := p.Bounds.Height / 2
+
+	for y := 0; y < p.Bounds.Height; y++ {
+		for x, eighth := range fill {
+			ch := eighths[eighth]
+			fg, bg := theme.Selected.Bg, theme.Normal.Bg
+
+			// Text over the bar is drawn in the colors of the part
+			// it's over
+			if y == row && x >= textLeft && x-textLeft < len(text) {
+				ch = text[x-textLeft]
+				fg, bg = theme.Normal.Fg, theme.Normal.Bg
+
+				if eighth >= 4 {
+					fg, bg = theme.Selected.Fg, theme.Selected.Bg
+				}
+			}
+
+			target.SetCell(x, y, fg, bg, ch)
+		}
+	}
+}
+
+// Frames for a Spinner
+var (
+	SpinnerLine   = []string{"-", "\\", "|", "/"}
+	SpinnerDots   = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	SpinnerBlocks = []string{"▖", "▘", "▝", "▗"}
+	SpinnerArc    = []string{"◜", "◠", "◝", "◞", "◡", "◟"}
+)
+
+// A Spinner shows that something is happening by cycling through Frames,
+// followed by Text. It animates on its own between Start and Stop; while
+// stopped, only Text is shown.
+type Spinner struct {
+	Invalidation
+
+	Bounds Rect
+
+	// Defaults to SpinnerLine
+	Frames []string
+
+	// Shown after the frame, like "Running query..."
+	Text string
+
+	// How long each frame is shown. Zero means 100ms.
+	Interval time.Duration
+
+	animation animation
+}
+
+func (s *Spinner) GetBounds() *Rect {
+	return &s.Bounds
+}
+
+// Start spinning.
+func (s *Spinner) Start() {
+	s.animation.start(s.Interval, s.Invalidate)
+	s.Invalidate()
+}
+
+// Stop spinning.
+func (s *Spinner) Stop() {
+	s.animation.stop()
+	s.Invalidate()
+}
+
+// Check whether the Spinner is spinning.
+func (s *Spinner) Running() bool {
+	return s.animation.timer != nil
+}
+
+func (s *Spinner) frames() []string {
+	if len(s.Frames) == 0 {
+		return SpinnerLine
+	}
+
+	return s.Frames
+}
+
+func (s *Spinner) Draw(target *DrawTarget) {
+	style := theme.Normal
+	text := s.Text
+
+	if s.Running() {
+		frames := s.frames()
+		text = frames[s.animation.frame%len(frames)] + " " + text
+	}
+
+	target.Print(0, 0, style.Fg, style.Bg, "%s", text)
+}
@@ -0,0 +1,64 @@
+package tui_test
+
+import (
+	"github.com/briansteffens/escapebox"
+	"github.com/briansteffens/tui"
+	"github.com/briansteffens/tui/tuitest"
+	"testing"
+	"time"
+)
+
+func TestProgressBarAndSpinner(t *testing.T) {
+	bar := &tui.ProgressBar{
+		Bounds:   tui.Rect{Width: 20, Height: 1},
+		Progress: 0.5,
+		Label:    "Loading",
+	}
+	fine := &tui.ProgressBar{
+		Bounds:         tui.Rect{Top: 1, Width: 20, Height: 1},
+		Progress:       0.53,
+		HidePercentage: true,
+	}
+	spinner := &tui.Spinner{
+		Bounds: tui.Rect{Top: 2, Width: 20, Height: 1},
+		Text:   "Running query",
+	}
+	defer spinner.Stop()
+
+	clock, restore := tui.UseFakeClock()
+	defer restore()
+
+	// Start the Spinner and move it on by a frame, which redraws it
+	// without any more input
+	c := &tui.Container{
+		Controls: []tui.Control{bar, fine, spinner},
+		EventHandler: func(c *tui.Container, ev escapebox.Event) bool {
+			switch ev.Ch {
+			case 's':
+				spinner.Start()
+			case 'f':
+				clock.Advance(100 * time.Millisecond)
+			}
+			return true
+		},
+	}
+
+	s := tuitest.Run(t, c, 20, 3, tuitest.Char('s'), tuitest.Char('f'),
+		tuitest.WaitForInterrupt())
+
+	theme := tui.CurrentTheme()
+
+	tuitest.AssertLine(t, s, 0, "████Loading 50%")
+	tuitest.AssertStyle(t, s, 2, 0, theme.Selected.Bg, theme.Normal.Bg)
+	tuitest.AssertStyle(t, s, 5, 0, theme.Selected.Fg, theme.Selected.Bg)
+	tuitest.AssertStyle(t, s, 12, 0, theme.Normal.Fg, theme.Normal.Bg)
+
+	tuitest.AssertLine(t, s, 1, "██████████▋")
+	tuitest.AssertLine(t, s, 2, "\\ Running query")
+
+	// Stopped, only the text is shown
+	spinner.Stop()
+	s = tuitest.Run(t, c, 20, 3)
+
+	tuitest.AssertLine(t, s, 2, "Running query")
+}
@@ -0,0 +1,208 @@
+package tui
+
+import (
+	"github.com/briansteffens/escapebox"
+	"github.com/nsf/termbox-go"
+)
+
+type RadioChangedEvent func(g *RadioGroup, value string)
+
+// A RadioGroup makes a set of RadioButtons mutually exclusive: selecting one
+// unselects the others. Add the buttons to the group with Add, in the order
+// the arrow keys move through them; the buttons themselves go in a Container
+// like any other control.
+//
+// Only the selected button, or the first one if none is selected, is visited
+// by the focus bindings, so Tab moves in and out of the group as a whole.
+type RadioGroup struct {
+	// Called after the selection changes
+	OnChanged RadioChangedEvent
+
+	buttons  []*RadioButton
+	selected *RadioButton
+}
+
+// Add buttons to the group.
+func (g *RadioGroup) Add(buttons ...*RadioButton) {
+	for _, b := range buttons {
+		b.group = g
+		g.buttons = append(g.buttons, b)
+	}
+}
+
+// The Value of the selected button, or "" if none is selected.
+func (g *RadioGroup) Selected() string {
+	if g.selected == nil {
+		return ""
+	}
+
+	return g.selected.value()
+}
+
+// Select the button with the given Value. Returns false if there isn't one.
+func (g *RadioGroup) Select(value string) bool {
+	for _, b := range g.buttons {
+		if b.value() == value {
+			g.selectButton(b)
+			return true
+		}
+	}
+
+	return false
+}
+
+func (g *RadioGroup) selectButton(b *RadioButton) {
+	if b == g.selected {
+		return
+	}
+
+	if g.selected != nil {
+		g.selected.Invalidate()
+	}
+
+	g.selected = b
+	b.Invalidate()
+
+	if g.OnChanged != nil {
+		g.OnChanged(g, b.value())
+	}
+}
+
+// The position of b in the group.
+func (g *RadioGroup) index(b *RadioButton) int {
+	for i, button := range g.buttons {
+		if button == b {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// One of the choices of a RadioGroup.
+type RadioButton struct {
+	Invalidation
+
+	Bounds Rect
+	Text   string
+
+	// What RadioGroup.Selected returns when the button is selected.
+	// Defaults to Text.
+	Value string
+
+	group     *RadioGroup
+	container *Container
+	focus     bool
+}
+
+func (b *RadioButton) GetBounds() *Rect {
+	return &b.Bounds
+}
+
+func (b *RadioButton) value() string {
+	if b.Value == "" {
+		return b.Text
+	}
+
+	return b.Value
+}
+
+// The RadioGroup the button was added to, if any.
+func (b *RadioButton) Group() *RadioGroup {
+	return b.group
+}
+
+// Check whether the button is the selected one in its group.
+func (b *RadioButton) Selected() bool {
+	return b.group != nil && b.group.selected == b
+}
+
+// Select the button, unselecting the rest of its group.
+func (b *RadioButton) Select() {
+	if b.group != nil {
+		b.group.selectButton(b)
+	}
+}
+
+func (b *RadioButton) placedIn(container *Container) {
+	b.container = container
+}
+
+// Only one button of a group is visited by the focus bindings.
+func (b *RadioButton) tabStop() bool {
+	g := b.group
+	if g == nil || g.selected == b {
+		return true
+	}
+
+	return g.selected == nil && len(g.buttons) > 0 && g.buttons[0] == b
+}
+
+func (b *RadioButton) Draw(target *DrawTarget) {
+	mark := " "
+
+	if b.Selected() {
+		mark = "*"
+	}
+
+	style := focusStyle(b.focus)
+	target.Print(0, 0, style.Fg, style.Bg, "(%s) %s", mark, b.Text)
+
+	if b.focus {
+		target.SetCursor(1, 0)
+	}
+}
+
+func (b *RadioButton) SetFocus() {
+	b.focus = true
+	b.Invalidate()
+}
+
+func (b *RadioButton) UnsetFocus() {
+	b.focus = false
+	b.Invalidate()
+}
+
+// Select and focus the button step places along in the group, wrapping
+// around at the ends.
+func (b *RadioButton) move(step int) {
+	g := b.group
+	if g == nil || len(g.buttons) < 2 {
+		return
+	}
+
+	count := len(g.buttons)
+	next := g.buttons[(g.index(b)+step+count)%count]
+
+	next.Select()
+
+	if b.container != nil {
+		b.container.focus(next)
+	}
+}
+
+// Select the button with Space or a click. The arrow keys move to the other
+// buttons in the group, selecting them.
+func (b *RadioButton) HandleEvent(ev escapebox.Event) bool {
+	if leftClick(ev) {
+		b.Select()
+		return true
+	}
+
+	if ev.Type != termbox.EventKey {
+		return false
+	}
+
+	switch ev.Key {
+	case termbox.KeySpace:
+		b.Select()
+	case termbox.KeyArrowUp, termbox.KeyArrowLeft:
+		b.move(-1)
+	case termbox.KeyArrowDown, termbox.KeyArrowRight:
+		b.move(1)
+	default:
+		return false
+	}
+
+	return true
+}
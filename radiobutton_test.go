@@ -0,0 +1,58 @@
+package tui_test
+
+import (
+	"fmt"
+	"github.com/briansteffens/tui"
+	"github.com/briansteffens/tui/tuitest"
+	"github.com/nsf/termbox-go"
+	"strings"
+	"testing"
+)
+
+func TestRadioGroup(t *testing.T) {
+	group := &tui.RadioGroup{}
+	buttons := []*tui.RadioButton{}
+
+	for i, text := range []string{"MySQL", "Postgres", "SQLite"} {
+		buttons = append(buttons, &tui.RadioButton{
+			Bounds: tui.Rect{Top: 3 + i, Width: 20, Height: 1},
+			Text:   text,
+			Value:  strings.ToLower(text),
+		})
+	}
+
+	group.Add(buttons...)
+	group.Select("postgres")
+
+	changes := []string{}
+	group.OnChanged = func(g *tui.RadioGroup, value string) {
+		changes = append(changes, value)
+	}
+
+	c := &tui.Container{
+		Controls: []tui.Control{
+			&tui.TextBox{Bounds: tui.Rect{Width: 20, Height: 3}},
+			buttons[0], buttons[1], buttons[2],
+			&tui.CheckBox{Bounds: tui.Rect{Top: 6, Width: 20,
+				Height: 1}, Text: "Read only"},
+		},
+		KeyBindingFocusNext: tui.KeyBinding{Key: termbox.KeyTab},
+		KeyBindingFocusPrevious: tui.KeyBinding{
+			Seq: tui.SeqShiftTab,
+		},
+	}
+
+	// Focus starts on the selected button. Down moves on to SQLite, Tab
+	// leaves the group, and Shift+Tab comes back to SQLite.
+	s := tuitest.Run(t, c, 20, 7, tuitest.Key(termbox.KeyArrowDown),
+		tuitest.Key(termbox.KeyTab), tuitest.Seq(tui.SeqShiftTab))
+
+	tuitest.AssertLine(t, s, 4, "( ) Postgres")
+	tuitest.AssertLine(t, s, 5, "(*) SQLite")
+
+	if c.Focused != buttons[2] || group.Selected() != "sqlite" ||
+		fmt.Sprint(changes) != "[sqlite]" {
+		t.Errorf("unexpected focus %v, selection %q and changes %v",
+			c.Focused, group.Selected(), changes)
+	}
+}
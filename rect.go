@@ -17,7 +17,26 @@ func (r *Rect) ContainsRect(other *Rect) bool {
 		other.Top >= r.Top && other.Bottom() <= r.Bottom()
 }
 
+func (r *Rect) Intersects(other *Rect) bool {
+	return other.Left <= r.Right() && other.Right() >= r.Left &&
+		other.Top <= r.Bottom() && other.Bottom() >= r.Top
+}
+
 func (r *Rect) ContainsPoint(x, y int) bool {
-	return x >= 0 && x < r.Width &&
-		y >= 0 && y < r.Height
+	return x >= r.Left && x <= r.Right() &&
+		y >= r.Top && y <= r.Bottom()
+}
+
+// The area covered by both Rects. If they don't overlap, the result has a
+// zero Width or Height.
+func (r *Rect) Intersection(other *Rect) Rect {
+	left := max(r.Left, other.Left)
+	top := max(r.Top, other.Top)
+
+	return Rect{
+		Left:   left,
+		Top:    top,
+		Width:  max(0, min(r.Right(), other.Right())-left+1),
+		Height: max(0, min(r.Bottom(), other.Bottom())-top+1),
+	}
 }
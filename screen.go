@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"github.com/briansteffens/escapebox"
+	"github.com/nsf/termbox-go"
+)
+
+// A Screen is the backend the library draws to and reads input from. By
+// default this is the terminal, driven by termbox and escapebox. Use
+// SetScreen to run against something else, like an in-memory screen in tests.
+//
+// Coordinates are always screen coordinates: (0, 0) is the top-left cell.
+type Screen interface {
+	SetCell(x, y int, ch rune, fg, bg Color)
+	SetCursor(x, y int)
+	HideCursor()
+	Size() (int, int)
+	Flush() error
+	PollEvent() escapebox.Event
+
+	// Make a blocked call to PollEvent return an event of type
+	// termbox.EventInterrupt. This may be called from any goroutine.
+	Interrupt()
+}
+
+// The terminal, which is also the default Screen
+var terminal = &termboxScreen{mode: ColorMode256}
+
+var screen Screen = terminal
+
+// Replace the Screen used by Refresh and MainLoop.
+func SetScreen(s Screen) {
+	screen = s
+}
+
+// Get the Screen currently used by Refresh and MainLoop.
+func CurrentScreen() Screen {
+	return screen
+}
+
+// Override the terminal's color support, which Init detects with
+// DetectColorMode. Colors are downgraded to fit the mode when drawn.
+func SetColorMode(mode ColorMode) {
+	terminal.mode = mode
+	termbox.SetOutputMode(termboxOutputMode(mode))
+
+	// Everything drawn so far used the old mode
+	themeChanged = true
+}
+
+// The default Screen, which forwards everything to termbox and escapebox.
+// Init and Close still need to be called around its use.
+type termboxScreen struct {
+	mode ColorMode
+}
+
+func (s *termboxScreen) SetCell(x, y int, ch rune, fg, bg Color) {
+	termbox.SetCell(x, y, ch, fg.toTermbox(s.mode, true),
+		bg.toTermbox(s.mode, false))
+}
+
+func (*termboxScreen) SetCursor(x, y int) {
+	termbox.SetCursor(x, y)
+}
+
+func (*termboxScreen) HideCursor() {
+	termbox.HideCursor()
+}
+
+func (*termboxScreen) Size() (int, int) {
+	return termbox.Size()
+}
+
+func (*termboxScreen) Flush() error {
+	return termbox.Flush()
+}
+
+func (*termboxScreen) PollEvent() escapebox.Event {
+	return escapebox.PollEvent()
+}
+
+func (*termboxScreen) Interrupt() {
+	termbox.Interrupt()
+}
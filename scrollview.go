@@ -0,0 +1,286 @@
+package tui
+
+import (
+	"github.com/briansteffens/escapebox"
+)
+
+// A ScrollView shows part of a Content control which can be larger than the
+// ScrollView itself. The size of Content's Bounds sets how much there is to
+// scroll through; its position is managed by the ScrollView. Scrollbars are
+// shown along the right and bottom edges when Content doesn't fit.
+//
+// Moving focus to a control inside Content scrolls it into view. The mouse
+// wheel scrolls too, as does clicking on a scrollbar.
+type ScrollView struct {
+	Invalidation
+
+	Bounds  Rect
+	Content Control
+
+	scrollX int
+	scrollY int
+
+	focused Focusable
+}
+
+func (s *ScrollView) GetBounds() *Rect {
+	return &s.Bounds
+}
+
+// The size of Content.
+func (s *ScrollView) contentSize() (int, int) {
+	if s.Content == nil {
+		return 0, 0
+	}
+
+	bounds := s.Content.GetBounds()
+	return bounds.Width, bounds.Height
+}
+
+// Work out the visible area and which scrollbars are needed.
+func (s *ScrollView) layout() (Rect, bool, bool) {
+	width, height := s.Bounds.Width, s.Bounds.Height
+	contentWidth, contentHeight := s.contentSize()
+
+	vertical := contentHeight > height
+	if vertical {
+		width--
+	}
+
+	horizontal := contentWidth > width
+	if horizontal {
+		height--
+	}
+
+	// The horizontal scrollbar can leave too little room for Content
+	if !vertical && contentHeight > height {
+		vertical = true
+		width--
+	}
+
+	return Rect{Width: max(0, width), Height: max(0, height)},
+		vertical, horizontal
+}
+
+// The part of the ScrollView which shows Content, in local coordinates.
+func (s *ScrollView) viewport() Rect {
+	view, _, _ := s.layout()
+	return view
+}
+
+// The current scroll position: how far Content is scrolled to the left and
+// up.
+func (s *ScrollView) Scroll() (int, int) {
+	return s.scrollX, s.scrollY
+}
+
+// Scroll so (x, y) in Content is at the top-left corner, or as close as
+// possible without scrolling past the end.
+func (s *ScrollView) ScrollTo(x, y int) {
+	view := s.viewport()
+	contentWidth, contentHeight := s.contentSize()
+
+	x = max(0, min(x, contentWidth-view.Width))
+	y = max(0, min(y, contentHeight-view.Height))
+
+	if x != s.scrollX || y != s.scrollY {
+		s.scrollX = x
+		s.scrollY = y
+		s.Invalidate()
+	}
+
+	s.arrange()
+}
+
+// Scroll by dx columns and dy rows.
+func (s *ScrollView) ScrollBy(dx, dy int) {
+	s.ScrollTo(s.scrollX+dx, s.scrollY+dy)
+}
+
+// Scroll as little as possible to show r, given in Content's coordinates.
+// If r doesn't fit, its top-left corner is shown.
+func (s *ScrollView) ScrollToRect(r Rect) {
+	view := s.viewport()
+	x, y := s.scrollX, s.scrollY
+
+	if r.Right() >= x+view.Width {
+		x = r.Right() - view.Width + 1
+	}
+
+	if r.Left < x {
+		x = r.Left
+	}
+
+	if r.Bottom() >= y+view.Height {
+		y = r.Bottom() - view.Height + 1
+	}
+
+	if r.Top < y {
+		y = r.Top
+	}
+
+	s.ScrollTo(x, y)
+}
+
+// Position Content according to the scroll position.
+func (s *ScrollView) arrange() {
+	if s.Content == nil {
+		return
+	}
+
+	bounds := s.Content.GetBounds()
+	bounds.Left = -s.scrollX
+	bounds.Top = -s.scrollY
+}
+
+func (s *ScrollView) Children() []Control {
+	if s.Content == nil {
+		return []Control{}
+	}
+
+	s.arrange()
+	return []Control{s.Content}
+}
+
+// Scroll newly focused controls into view.
+func (s *ScrollView) focusChanged(f Focusable) {
+	if f == s.focused {
+		return
+	}
+
+	s.focused = f
+
+	if f == nil || s.Content == nil {
+		return
+	}
+
+	bounds, ok := findBounds([]Control{s.Content}, f)
+	if !ok {
+		return
+	}
+
+	// Content's position includes the current scroll
+	bounds.Left += s.scrollX
+	bounds.Top += s.scrollY
+
+	s.ScrollToRect(bounds)
+}
+
+// Check whether the ScrollView or its Content need to be redrawn.
+func (s *ScrollView) Invalid() bool {
+	return s.Invalidation.Invalid() ||
+		(s.Content != nil && needsDraw(s.Content))
+}
+
+func (s *ScrollView) Draw(target *DrawTarget) {
+	view, vertical, horizontal := s.layout()
+
+	// Scrolling may be out of range if the ScrollView or Content changed
+	// size since.
+	s.ScrollBy(0, 0)
+
+	if vertical {
+		s.drawScrollbar(target, view.Width, 0, view.Height, true)
+	}
+
+	if horizontal {
+		s.drawScrollbar(target, 0, view.Height, view.Width, false)
+	}
+
+	if s.Content == nil {
+		return
+	}
+
+	viewTarget, err := target.Slice(&view)
+	if err != nil {
+		target.ReportError(err)
+		return
+	}
+
+	drawChild(viewTarget, s.Content)
+}
+
+// The position and length of a scrollbar's thumb along a track of the given
+// length, showing visible cells out of total starting at offset.
+func thumb(length, visible, total, offset int) (int, int) {
+	scrollable := total - visible
+	if total <= 0 || scrollable <= 0 {
+		return 0, length
+	}
+
+	size := max(1, length*visible/total)
+
+	return offset * (length - size) / scrollable, size
+}
+
+// Draw a scrollbar of the given length starting at (x, y).
+func (s *ScrollView) drawScrollbar(target *DrawTarget, x, y, length int,
+	vertical bool) {
+	view := s.viewport()
+	contentWidth, contentHeight := s.contentSize()
+
+	var start, size int
+	track := '─'
+
+	if vertical {
+		start, size = thumb(length, view.Height, contentHeight,
+			s.scrollY)
+		track = '│'
+	} else {
+		start, size = thumb(length, view.Width, contentWidth, s.scrollX)
+	}
+
+	style := theme.Border
+
+	for i := 0; i < length; i++ {
+		ch := track
+		if i >= start && i < start+size {
+			ch = '█'
+		}
+
+		if vertical {
+			target.SetCell(x, y+i, style.Fg, style.Bg, ch)
+		} else {
+			target.SetCell(x+i, y, style.Fg, style.Bg, ch)
+		}
+	}
+}
+
+// A ScrollView never has focus itself, only the controls inside it, so
+// SetFocus and UnsetFocus do nothing. It's Focusable so that it gets mouse
+// events the controls inside it don't handle.
+func (s *ScrollView) SetFocus() {
+}
+
+func (s *ScrollView) UnsetFocus() {
+}
+
+// Scroll with the mouse wheel, or by clicking on a scrollbar to jump to that
+// point.
+func (s *ScrollView) HandleEvent(ev escapebox.Event) bool {
+	if dir := wheelDirection(ev); dir != 0 {
+		s.ScrollBy(0, dir*wheelLines)
+		return true
+	}
+
+	if !leftClick(ev) {
+		return false
+	}
+
+	view, vertical, horizontal := s.layout()
+	contentWidth, contentHeight := s.contentSize()
+
+	if vertical && ev.MouseX == view.Width && ev.MouseY < view.Height {
+		s.ScrollTo(s.scrollX, ev.MouseY*(contentHeight-view.Height)/
+			max(1, view.Height-1))
+		return true
+	}
+
+	if horizontal && ev.MouseY == view.Height && ev.MouseX < view.Width {
+		s.ScrollTo(ev.MouseX*(contentWidth-view.Width)/
+			max(1, view.Width-1), s.scrollY)
+		return true
+	}
+
+	return false
+}
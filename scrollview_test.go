@@ -0,0 +1,73 @@
+package tui_test
+
+import (
+	"fmt"
+	"github.com/briansteffens/escapebox"
+	"github.com/briansteffens/tui"
+	"github.com/briansteffens/tui/tuitest"
+	"github.com/nsf/termbox-go"
+	"testing"
+)
+
+func TestScrollViewFollowsFocus(t *testing.T) {
+	form := &tui.Container{Bounds: tui.Rect{Width: 20, Height: 30}}
+
+	for i := 0; i < 10; i++ {
+		form.Controls = append(form.Controls, &tui.TextBox{
+			Bounds: tui.Rect{Top: i * 3, Width: 20, Height: 3},
+			Value:  fmt.Sprintf("field %d", i),
+			Border: tui.BorderSingle,
+		})
+	}
+
+	view := &tui.ScrollView{
+		Bounds:  tui.Rect{Width: 21, Height: 9},
+		Content: form,
+	}
+
+	c := &tui.Container{
+		Controls:            []tui.Control{view},
+		KeyBindingFocusNext: tui.KeyBinding{Key: termbox.KeyTab},
+	}
+
+	// Focus starts on field 1 and moves to field 8, at the bottom
+	events := []escapebox.Event{}
+	for i := 0; i < 7; i++ {
+		events = append(events, tuitest.Key(termbox.KeyTab))
+	}
+
+	s := tuitest.Run(t, c, 30, 10, events...)
+
+	if x, y := view.Scroll(); x != 0 || y != 18 {
+		t.Errorf("expected to scroll to 0,18, got %d,%d", x, y)
+	}
+
+	tuitest.AssertLine(t, s, 7, "│field 8           │█")
+
+	// Starting MainLoop again moves focus on to field 9, scrolling to the
+	// end. The TextBox under the pointer ignores the wheel, so the
+	// ScrollView scrolls back up, hiding field 9 and the cursor.
+	s = tuitest.Run(t, c, 30, 10, tuitest.Mouse(termbox.MouseWheelUp, 5, 1))
+
+	if _, y := view.Scroll(); y != 18 {
+		t.Errorf("expected to scroll up to 18, got %d", y)
+	}
+
+	if _, _, visible := s.Cursor(); visible {
+		t.Errorf("expected the cursor to be hidden")
+	}
+}
+
+func TestScrollViewEmptyWidth(t *testing.T) {
+	view := &tui.ScrollView{
+		Bounds:  tui.Rect{Width: 10, Height: 5},
+		Content: &tui.Label{Bounds: tui.Rect{Height: 20}},
+	}
+
+	// Only the vertical scrollbar is drawn, with its thumb at the top
+	s := tuitest.Run(t, &tui.Container{Controls: []tui.Control{view}},
+		10, 5)
+
+	tuitest.AssertLine(t, s, 0, "         █")
+	tuitest.AssertLine(t, s, 4, "         │")
+}
@@ -1,10 +1,13 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"github.com/briansteffens/escapebox"
 	"github.com/nsf/termbox-go"
-	"os"
+	"io"
+	"runtime/debug"
+	"time"
 )
 
 func min(a, b int) int {
@@ -21,15 +24,65 @@ func max(a, b int) int {
 	return b
 }
 
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
 // Non-standard escape sequences
 const (
 	SeqShiftTab = 1
+	SeqAltUp    = 2
+	SeqAltDown  = 3
+	SeqAltRight = 4
+	SeqAltLeft  = 5
+
+	SeqCtrlPageUp   = 6
+	SeqCtrlPageDown = 7
+
+	// Alt+1 to Alt+9 are numbered in order
+	SeqAlt1 = 8
+	SeqAlt9 = SeqAlt1 + 8
+
+	// Alt+A to Alt+Z are lettered in order
+	SeqAltA = SeqAlt9 + 1
+	SeqAltZ = SeqAltA + 25
 )
 
 func renderableChar(ev escapebox.Event) bool {
 	return ev.Type == termbox.EventKey && ev.Key == 0 && ev.Ch != 0
 }
 
+// Check if an event is a press of the left mouse button. Moving the mouse
+// with the button held down is also reported as MouseLeft, but with
+// ModMotion set, and isn't a press.
+func leftClick(ev escapebox.Event) bool {
+	return ev.Type == termbox.EventMouse && ev.Key == termbox.MouseLeft &&
+		ev.Mod&termbox.ModMotion == 0
+}
+
+// Number of lines a single mouse wheel event scrolls
+const wheelLines = 3
+
+// Get the scroll direction of a mouse wheel event: -1 for up, 1 for down and
+// 0 if the event isn't a wheel event.
+func wheelDirection(ev escapebox.Event) int {
+	if ev.Type != termbox.EventMouse {
+		return 0
+	}
+
+	switch ev.Key {
+	case termbox.MouseWheelUp:
+		return -1
+	case termbox.MouseWheelDown:
+		return 1
+	}
+
+	return 0
+}
+
 type KeyBinding struct {
 	Seq escapebox.Sequence
 	Key termbox.Key
@@ -62,6 +115,17 @@ type Control interface {
 	Draw(*DrawTarget)
 }
 
+// Controls which contain other controls, like Container. Focus traversal and
+// mouse events descend into their children, whose Bounds are relative to the
+// parent's top-left corner.
+type Parent interface {
+	Control
+	Children() []Control
+}
+
+// Controls which can receive focus and input. Mouse events are passed to
+// HandleEvent with MouseX and MouseY translated into the control's local
+// coordinates.
 type Focusable interface {
 	Control
 	SetFocus()
@@ -69,71 +133,161 @@ type Focusable interface {
 	HandleEvent(escapebox.Event) bool
 }
 
-func Init() {
-	err := termbox.Init()
-	if err != nil {
-		panic(err)
+// Whether Init has set up the terminal and Close hasn't restored it yet
+var initialized bool
+
+// Set up the terminal for drawing and input. Call Close when done to restore
+// it, even if MainLoop returns an error.
+func Init() error {
+	if err := termbox.Init(); err != nil {
+		return err
 	}
 
-	termbox.SetInputMode(termbox.InputEsc) // | termbox.InputMouse)
-	termbox.SetOutputMode(termbox.Output256)
+	termbox.SetInputMode(termbox.InputEsc | termbox.InputMouse)
+	SetColorMode(DetectColorMode())
 
 	escapebox.Init()
 	escapebox.Register(SeqShiftTab, 91, 90)
+	escapebox.Register(SeqAltUp, 91, 49, 59, 51, 65)
+	escapebox.Register(SeqAltDown, 91, 49, 59, 51, 66)
+	escapebox.Register(SeqAltRight, 91, 49, 59, 51, 67)
+	escapebox.Register(SeqAltLeft, 91, 49, 59, 51, 68)
+	escapebox.Register(SeqCtrlPageUp, 91, 53, 59, 53, 126)
+	escapebox.Register(SeqCtrlPageDown, 91, 54, 59, 53, 126)
+
+	for i := 0; i < 9; i++ {
+		escapebox.Register(escapebox.Sequence(SeqAlt1+i), byte('1'+i))
+	}
+
+	for i := 0; i < 26; i++ {
+		escapebox.Register(escapebox.Sequence(SeqAltA+i), byte('a'+i))
+	}
+
+	initialized = true
+	return nil
 }
 
+// Restore the terminal to the state it was in before Init. It's safe to call
+// Close more than once, or without calling Init.
 func Close() {
+	if !initialized {
+		return
+	}
+
 	escapebox.Close()
 	termbox.Close()
+
+	initialized = false
 }
 
-func log(message string, args ...interface{}) {
-	mode := os.O_APPEND | os.O_WRONLY | os.O_CREATE
-	f, err := os.OpenFile(".tui-log", mode, 0644)
-	if err != nil {
-		panic(err)
-	}
+// A PanicError is returned by MainLoop when something running on the UI
+// goroutine panics.
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
 
-	defer f.Close()
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v\n\n%s", e.Value, e.Stack)
+}
 
-	if _, err = fmt.Fprintf(f, message, args...); err != nil {
-		panic(err)
+// Redraw whatever has been invalidated in root and flush it to the screen.
+// If nothing is invalid, the screen isn't touched. Controls which couldn't be
+// drawn, for example because they don't fit on the screen, are skipped and
+// reported in the returned error.
+func Refresh(root *Container) error {
+	if themeChanged {
+		root.Invalidate()
+		themeChanged = false
 	}
 
-	if _, err = fmt.Fprintln(f); err != nil {
-		panic(err)
+	if !root.Invalid() {
+		return nil
 	}
-}
 
-func Refresh(root *Container) {
-	target := fullTerminalDrawTarget()
+	start := time.Now()
+
+	target := fullScreenDrawTarget(screen)
 	root.Draw(target)
-	termbox.Flush()
+
+	errs := *target.errs
+
+	if err := screen.Flush(); err != nil {
+		errs = append(errs, err)
+	}
+
+	logger.Debug("refreshed", "duration", time.Since(start))
+
+	return errors.Join(errs...)
 }
 
-func MainLoop(c *Container) {
+// Run the UI: draw c and dispatch input events to it until the exit key
+// binding is pressed. All event handlers, and functions queued with Post, run
+// on the goroutine which called MainLoop.
+//
+// MainLoop returns an error if drawing fails or the screen reports an input
+// error. If anything on the UI goroutine panics, the terminal is restored
+// with Close and the panic is returned as a *PanicError.
+func MainLoop(c *Container) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			Close()
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	stopWaker := startWaker(screen)
+	defer stopWaker()
+
+	c.parent = nil
+	c.resize(screen.Size())
+	c.Arrange()
 	c.FocusNext()
 
-	c.Width, c.Height = termbox.Size()
 	if c.ResizeHandler != nil {
 		c.ResizeHandler()
 	}
 
-	Refresh(c)
+	runPosted()
+
+	if err := Refresh(c); err != nil {
+		return err
+	}
 
-loop:
 	for {
-		ev := escapebox.PollEvent()
+		ev := screen.PollEvent()
+
+		// Controls may have been added or moved between Containers
+		c.adopt()
+
+		// The screen has run out of input, as an in-memory screen does
+		// at the end of its script.
+		if ev.Type == termbox.EventError && ev.Err == io.EOF {
+			return nil
+		}
+
+		if ev.Type == termbox.EventError {
+			return ev.Err
+		}
 
 		if matchBinding(ev, c.KeyBindingExit) {
-			break loop
+			return nil
 		}
 
 		handled := false
 
-		if ev.Type == termbox.EventResize {
-			c.Width = ev.Width
-			c.Height = ev.Height
+		if ev.Type == termbox.EventInterrupt {
+			runPosted()
+			handled = true
+		}
+
+		if !handled && ev.Type == termbox.EventResize {
+			c.resize(ev.Width, ev.Height)
+			c.Arrange()
+			c.Invalidate()
+
+			logger.Debug("resized", "width", c.Width,
+				"height", c.Height)
 
 			if c.ResizeHandler != nil {
 				c.ResizeHandler()
@@ -142,24 +296,104 @@ loop:
 			handled = true
 		}
 
+		// Popups get the mouse events over them, and clicks anywhere
+		// else close the open menus or open a MenuBar's menu. Otherwise
+		// an open Dialog gets all mouse events, even ones outside it, and
+		// Windows get the events over them, unless something underneath
+		// is being dragged.
+		if !handled && ev.Type == termbox.EventMouse {
+			w := c.windowAt(ev.MouseX, ev.MouseY)
+
+			p := c.popupAt(ev.MouseX, ev.MouseY)
+
+			if p != nil && c.captured == nil {
+				handled = handlePopupMouse(p, ev)
+			} else if c.captured == nil && c.handleMenuMouse(ev) {
+				handled = true
+			} else if d := c.topDialog(); d != nil {
+				handled = d.handleMouse(ev)
+			} else if w != nil && c.captured == nil {
+				handled = w.handleMouse(ev)
+			} else {
+				handled = c.handleMouse(ev)
+			}
+		}
+
+		// Open menus take over the keyboard
+		if !handled && c.menu != nil {
+			handled = c.menu.handleKey(ev)
+		}
+
+		// Moving or resizing a Window does too
+		if !handled && c.windowMode != windowModeNone &&
+			c.topDialog() == nil {
+			handled = c.handleWindowKey(ev)
+		}
+
 		if !handled && c.Focused != nil {
 			handled = c.Focused.HandleEvent(ev)
 		}
 
+		// Let the controls around the focused one have a go at it,
+		// innermost first: nested Containers' EventHandlers and other
+		// Focusable Parents, like TabView.
+		if !handled && c.Focused != nil && ev.Type != termbox.EventMouse {
+			path := c.ancestors(c.Focused)
+
+			for i := len(path) - 1; i >= 0 && !handled; i-- {
+				switch p := path[i].(type) {
+				case *Container:
+					if p.EventHandler != nil {
+						handled = p.EventHandler(p, ev)
+					}
+				case Focusable:
+					handled = p.HandleEvent(ev)
+				}
+			}
+		}
+
+		// MenuBars' bindings work anywhere, unless a Dialog is open
+		if !handled && c.topDialog() == nil {
+			handled = c.handleMenuKey(ev)
+		}
+
+		// Focus stays inside the top Dialog while there is one, or
+		// otherwise the focused Window
 		if !handled && matchBinding(ev, c.KeyBindingFocusNext) {
-			c.FocusNext()
+			c.active().FocusNext()
 			handled = true
 		}
 
 		if !handled && matchBinding(ev, c.KeyBindingFocusPrevious) {
-			c.FocusPrevious()
+			c.active().FocusPrevious()
 			handled = true
 		}
 
-		if !handled && c.HandleEvent != nil {
-			handled = c.HandleEvent(c, ev)
+		if !handled {
+			for dir, kb := range c.directionBindings() {
+				if matchBinding(ev, kb) {
+					c.active().FocusDirection(Direction(dir))
+					handled = true
+					break
+				}
+			}
+		}
+
+		if !handled && c.topDialog() == nil {
+			handled = c.handleWindowKey(ev)
+		}
+
+		if !handled && c.topDialog() == nil && c.EventHandler != nil {
+			handled = c.EventHandler(c, ev)
+		}
+
+		if !handled && ev.Type == termbox.EventKey {
+			logger.Debug("unhandled key event", "key", ev.Key,
+				"ch", string(ev.Ch), "seq", ev.Seq)
 		}
 
-		Refresh(c)
+		if err := Refresh(c); err != nil {
+			return err
+		}
 	}
 }
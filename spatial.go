@@ -0,0 +1,160 @@
+package tui
+
+// A direction to move focus in with Container.FocusDirection.
+type Direction int
+
+const (
+	DirectionUp Direction = iota
+	DirectionDown
+	DirectionLeft
+	DirectionRight
+)
+
+// A Focusable control and where it is relative to the Container doing the
+// search.
+type focusCandidate struct {
+	control Focusable
+	bounds  Rect
+}
+
+// Find the Focusable controls in controls and their Bounds relative to the
+// outermost control list, descending into Parents.
+func focusCandidates(controls []Control, left, top int) []focusCandidate {
+	ret := []focusCandidate{}
+
+	for _, ctrl := range controls {
+		bounds := *ctrl.GetBounds()
+		bounds.Left += left
+		bounds.Top += top
+
+		if p, ok := ctrl.(Parent); ok {
+			ret = append(ret, focusCandidates(p.Children(),
+				bounds.Left, bounds.Top)...)
+			continue
+		}
+
+		if f, ok := ctrl.(Focusable); ok {
+			ret = append(ret, focusCandidate{f, bounds})
+		}
+	}
+
+	return ret
+}
+
+// Measure a Rect along the direction of movement (main) and across it
+// (cross), as start and end coordinates. Up and Left are flipped so that
+// moving always means going towards larger main coordinates.
+func axes(r Rect, dir Direction) (int, int, int, int) {
+	switch dir {
+	case DirectionUp:
+		return -r.Bottom(), -r.Top, r.Left, r.Right()
+	case DirectionDown:
+		return r.Top, r.Bottom(), r.Left, r.Right()
+	case DirectionLeft:
+		return -r.Right(), -r.Left, r.Top, r.Bottom()
+	}
+
+	return r.Left, r.Right(), r.Top, r.Bottom()
+}
+
+// The gap between two ranges, or 0 if they overlap.
+func rangeGap(start1, end1, start2, end2 int) int {
+	return max(0, max(start1-end2, start2-end1))
+}
+
+// Move focus to the nearest Focusable control in the given direction, based
+// on the controls' Bounds. Controls beside the focused one (overlapping it
+// across the direction of movement) are preferred, then the closest ones, then
+// the ones best lined up with it. Ties go to the earlier control in focus
+// order.
+//
+// If there's nothing in that direction, focus wraps around to the farthest
+// control on the other side which is beside the focused one. On a nested
+// Container this only moves between the controls inside it.
+func (c *Container) FocusDirection(dir Direction) {
+	candidates := focusCandidates(c.Controls, 0, 0)
+
+	var current *focusCandidate
+	for i := range candidates {
+		if candidates[i].control == c.Focused {
+			current = &candidates[i]
+		}
+	}
+
+	if current == nil {
+		c.FocusNext()
+		return
+	}
+
+	curStart, curEnd, curCrossStart, curCrossEnd := axes(current.bounds, dir)
+
+	var best Focusable
+	var bestScore [3]int
+
+	better := func(score [3]int) bool {
+		for i := range score {
+			if score[i] != bestScore[i] {
+				return score[i] < bestScore[i]
+			}
+		}
+		return false
+	}
+
+	for _, candidate := range candidates {
+		if candidate.control == current.control {
+			continue
+		}
+
+		start, end, crossStart, crossEnd := axes(candidate.bounds, dir)
+
+		// Only controls whose center is past the focused control's
+		if start+end <= curStart+curEnd {
+			continue
+		}
+
+		beside := 0
+		if rangeGap(crossStart, crossEnd, curCrossStart,
+			curCrossEnd) > 0 {
+			beside = 1
+		}
+
+		score := [3]int{
+			beside,
+			rangeGap(start, end, curStart, curEnd),
+			abs(crossStart + crossEnd - curCrossStart - curCrossEnd),
+		}
+
+		if best == nil || better(score) {
+			best = candidate.control
+			bestScore = score
+		}
+	}
+
+	if best == nil {
+		// Wrap around to the farthest control on the other side
+		for _, candidate := range candidates {
+			if candidate.control == current.control {
+				continue
+			}
+
+			start, _, crossStart, crossEnd := axes(candidate.bounds, dir)
+
+			if rangeGap(crossStart, crossEnd, curCrossStart,
+				curCrossEnd) > 0 {
+				continue
+			}
+
+			score := [3]int{start, abs(crossStart + crossEnd -
+				curCrossStart - curCrossEnd)}
+
+			if best == nil || better(score) {
+				best = candidate.control
+				bestScore = score
+			}
+		}
+	}
+
+	if best != nil {
+		c.focus(best)
+	}
+}
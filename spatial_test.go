@@ -0,0 +1,32 @@
+package tui_test
+
+import (
+	"github.com/briansteffens/tui"
+	"github.com/briansteffens/tui/tuitest"
+	"testing"
+)
+
+func TestSpatialFocus(t *testing.T) {
+	topLeft := &tui.TextBox{Bounds: tui.Rect{Width: 20, Height: 3}}
+	topRight := &tui.TextBox{Bounds: tui.Rect{Left: 20, Width: 20,
+		Height: 3}}
+	bottom := &tui.TextBox{Bounds: tui.Rect{Top: 3, Width: 40, Height: 3}}
+
+	c := &tui.Container{
+		Controls:            []tui.Control{topLeft, topRight, bottom},
+		KeyBindingFocusLeft: tui.KeyBinding{Seq: tui.SeqAltLeft},
+		KeyBindingFocusDown: tui.KeyBinding{Seq: tui.SeqAltDown},
+	}
+
+	// Focus starts on the top right. There's nothing to the left of the
+	// top left TextBox, so focus wraps around to the top right again.
+	tuitest.Run(t, c, 40, 6, tuitest.Seq(tui.SeqAltLeft),
+		tuitest.Char('a'), tuitest.Seq(tui.SeqAltLeft), tuitest.Char('r'),
+		tuitest.Seq(tui.SeqAltDown), tuitest.Char('b'))
+
+	if topLeft.Value != "a" || topRight.Value != "r" ||
+		bottom.Value != "b" {
+		t.Errorf("unexpected values %q, %q and %q", topLeft.Value,
+			topRight.Value, bottom.Value)
+	}
+}
@@ -0,0 +1,296 @@
+package tui
+
+import (
+	"github.com/briansteffens/escapebox"
+	"github.com/nsf/termbox-go"
+	"math"
+)
+
+// How a Split arranges its two sides.
+type Orientation int
+
+const (
+	// First on the left, Second on the right
+	Horizontal Orientation = iota
+
+	// First on top, Second below
+	Vertical
+)
+
+// Which side of a Split is collapsed, if any.
+type Collapsed int
+
+const (
+	CollapsedNone Collapsed = iota
+	CollapsedFirst
+	CollapsedSecond
+)
+
+// A Split shows two controls next to each other with a divider between them.
+// The divider can be moved with the key bindings or by dragging it with the
+// mouse, and either side can be collapsed to give the other all the space.
+//
+// The divider's position is kept as a ratio, so the sides keep their
+// proportions when the Split is resized.
+type Split struct {
+	Invalidation
+
+	Bounds      Rect
+	Orientation Orientation
+	First       Control
+	Second      Control
+
+	// The share of the space First gets, from 0 to 1. Zero splits the
+	// space evenly.
+	Ratio float64
+
+	// The smallest sizes the divider can make each side, unless it's
+	// collapsed
+	MinFirst  int
+	MinSecond int
+
+	// Move the divider one cell, making First bigger or smaller
+	KeyBindingGrow   KeyBinding
+	KeyBindingShrink KeyBinding
+
+	// Collapse the side which doesn't have focus, or expand it again
+	KeyBindingCollapse KeyBinding
+
+	collapsed   Collapsed
+	dragging    bool
+	focusFirst  bool
+	focusWithin bool
+}
+
+func (s *Split) GetBounds() *Rect {
+	return &s.Bounds
+}
+
+// The space the two sides share, leaving room for the divider.
+func (s *Split) available() int {
+	length := s.Bounds.Width
+	if s.Orientation == Vertical {
+		length = s.Bounds.Height
+	}
+
+	return max(0, length-1)
+}
+
+func (s *Split) ratio() float64 {
+	if s.Ratio <= 0 {
+		return 0.5
+	}
+
+	return math.Min(s.Ratio, 1)
+}
+
+// The size of First, which is also the position of the divider.
+func (s *Split) firstSize() int {
+	available := s.available()
+
+	switch s.collapsed {
+	case CollapsedFirst:
+		return 0
+	case CollapsedSecond:
+		return available
+	}
+
+	size := int(math.Round(s.ratio() * float64(available)))
+	size = min(size, available-s.MinSecond)
+
+	return max(0, min(max(size, s.MinFirst), available))
+}
+
+// Size the two sides around the divider.
+func (s *Split) arrange() {
+	first := s.firstSize()
+	second := s.available() - first
+
+	firstBounds := Rect{Width: first, Height: s.Bounds.Height}
+	secondBounds := Rect{Left: first + 1, Width: second,
+		Height: s.Bounds.Height}
+
+	if s.Orientation == Vertical {
+		firstBounds = Rect{Width: s.Bounds.Width, Height: first}
+		secondBounds = Rect{Top: first + 1, Width: s.Bounds.Width,
+			Height: second}
+	}
+
+	if s.First != nil {
+		*s.First.GetBounds() = firstBounds
+	}
+
+	if s.Second != nil {
+		*s.Second.GetBounds() = secondBounds
+	}
+}
+
+// The sides which are shown. A collapsed side takes no part in focus or
+// mouse handling.
+func (s *Split) Children() []Control {
+	s.arrange()
+
+	ret := []Control{}
+
+	if s.First != nil && s.collapsed != CollapsedFirst {
+		ret = append(ret, s.First)
+	}
+
+	if s.Second != nil && s.collapsed != CollapsedSecond {
+		ret = append(ret, s.Second)
+	}
+
+	return ret
+}
+
+func (s *Split) focusChanged(f Focusable) {
+	focusWithin := f != nil
+
+	if focusWithin && s.First != nil {
+		_, s.focusFirst = ancestorsIn([]Control{s.First}, f)
+	}
+
+	if focusWithin != s.focusWithin {
+		s.focusWithin = focusWithin
+		s.Invalidate()
+	}
+}
+
+// Move the divider to make First the given size, keeping both sides at least
+// one cell and their minimum sizes. This expands a collapsed side.
+func (s *Split) SetFirstSize(size int) {
+	available := s.available()
+
+	size = min(size, available-max(1, s.MinSecond))
+	size = max(size, max(1, s.MinFirst))
+
+	// Too small to show both sides
+	if size >= available {
+		return
+	}
+
+	s.Ratio = float64(size) / float64(available)
+	s.collapsed = CollapsedNone
+	s.Invalidate()
+}
+
+// Which side is collapsed, if any.
+func (s *Split) Collapsed() Collapsed {
+	return s.collapsed
+}
+
+// Collapse one side, giving the other all the space, or expand it again with
+// CollapsedNone. The divider remembers where it was. Focus isn't moved out of
+// a side being collapsed.
+func (s *Split) Collapse(side Collapsed) {
+	if side != s.collapsed {
+		s.collapsed = side
+		s.Invalidate()
+	}
+}
+
+// Check whether the Split or either side need to be redrawn.
+func (s *Split) Invalid() bool {
+	if s.Invalidation.Invalid() {
+		return true
+	}
+
+	for _, child := range s.Children() {
+		if needsDraw(child) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Split) Draw(target *DrawTarget) {
+	s.arrange()
+
+	style := borderStyle(s.focusWithin || s.dragging)
+	divider := s.firstSize()
+
+	if s.Orientation == Vertical {
+		for x := 0; x < s.Bounds.Width; x++ {
+			target.SetCell(x, divider, style.Fg, style.Bg, '─')
+		}
+	} else {
+		for y := 0; y < s.Bounds.Height; y++ {
+			target.SetCell(divider, y, style.Fg, style.Bg, '│')
+		}
+	}
+
+	for _, child := range s.Children() {
+		drawChild(target, child)
+	}
+}
+
+// A Split never has focus itself, only the controls inside it, so SetFocus
+// and UnsetFocus do nothing. It's Focusable so that it gets the events the
+// controls inside it don't handle.
+func (s *Split) SetFocus() {
+}
+
+func (s *Split) UnsetFocus() {
+}
+
+// Check whether the divider is being dragged with the mouse, so the root
+// Container keeps sending it mouse events.
+func (s *Split) capturesMouse() bool {
+	return s.dragging
+}
+
+// Move the divider with the key bindings or the mouse.
+func (s *Split) HandleEvent(ev escapebox.Event) bool {
+	if ev.Type == termbox.EventMouse {
+		return s.handleMouseEvent(ev)
+	}
+
+	switch {
+	case matchBinding(ev, s.KeyBindingGrow):
+		s.SetFirstSize(s.firstSize() + 1)
+	case matchBinding(ev, s.KeyBindingShrink):
+		s.SetFirstSize(s.firstSize() - 1)
+	case matchBinding(ev, s.KeyBindingCollapse):
+		switch {
+		case s.collapsed != CollapsedNone:
+			s.Collapse(CollapsedNone)
+		case s.focusFirst:
+			s.Collapse(CollapsedSecond)
+		default:
+			s.Collapse(CollapsedFirst)
+		}
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (s *Split) handleMouseEvent(ev escapebox.Event) bool {
+	position := ev.MouseX
+	if s.Orientation == Vertical {
+		position = ev.MouseY
+	}
+
+	if s.dragging {
+		if ev.Key == termbox.MouseRelease {
+			s.dragging = false
+			s.Invalidate()
+		} else if position != s.firstSize() {
+			s.SetFirstSize(position)
+		}
+
+		return true
+	}
+
+	// Only presses on the divider start a drag
+	if !leftClick(ev) || position != s.firstSize() {
+		return false
+	}
+
+	s.dragging = true
+	s.Invalidate()
+
+	return true
+}
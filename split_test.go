@@ -0,0 +1,50 @@
+package tui_test
+
+import (
+	"github.com/briansteffens/tui"
+	"github.com/briansteffens/tui/tuitest"
+	"github.com/nsf/termbox-go"
+	"testing"
+)
+
+func TestSplitDragAndResize(t *testing.T) {
+	editor := &tui.TextBox{}
+	results := &tui.TextBox{}
+
+	split := &tui.Split{
+		Orientation:        tui.Vertical,
+		First:              editor,
+		Second:             results,
+		MinFirst:           3,
+		KeyBindingCollapse: tui.KeyBinding{Key: termbox.KeyCtrlW},
+	}
+
+	c := &tui.Container{
+		Controls: []tui.Control{split},
+		Layout:   &tui.VBox{},
+	}
+
+	// Drag the divider from row 5 to row 7, over the results, then make
+	// the window twice as tall.
+	tuitest.Run(t, c, 20, 11, tuitest.Click(0, 5),
+		tuitest.Mouse(termbox.MouseLeft, 0, 7),
+		tuitest.Mouse(termbox.MouseRelease, 0, 7),
+		tuitest.Resize(20, 21))
+
+	if *editor.GetBounds() != (tui.Rect{Width: 20, Height: 14}) ||
+		*results.GetBounds() != (tui.Rect{Top: 15, Width: 20,
+			Height: 6}) {
+		t.Errorf("unexpected bounds %+v and %+v", *editor.GetBounds(),
+			*results.GetBounds())
+	}
+
+	// Starting MainLoop again moves focus on to the editor, so the
+	// results collapse.
+	tuitest.Run(t, c, 20, 21, tuitest.Key(termbox.KeyCtrlW))
+
+	if split.Collapsed() != tui.CollapsedSecond ||
+		*editor.GetBounds() != (tui.Rect{Width: 20, Height: 20}) {
+		t.Errorf("expected the results to collapse, got %+v",
+			*editor.GetBounds())
+	}
+}
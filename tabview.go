@@ -0,0 +1,266 @@
+package tui
+
+import (
+	"github.com/briansteffens/escapebox"
+	"github.com/nsf/termbox-go"
+)
+
+// One page of a TabView.
+type Tab struct {
+	Title   string
+	Content *Container
+
+	// What had focus in the tab when it was last switched away from
+	lastFocus Focusable
+}
+
+type TabChangedEvent func(t *TabView, previous int)
+
+// A TabView shows one of several pages of controls at a time, with a strip of
+// tab titles along the top. Each tab's Content fills the rest of the TabView.
+//
+// Switching tabs moves focus into the new tab, to whatever had it when the
+// tab was last shown, or otherwise to its first Focusable control. Tabs can be
+// switched by clicking on their titles, with the key bindings, or with
+// SetActive.
+type TabView struct {
+	Invalidation
+
+	Bounds Rect
+	Tabs   []*Tab
+
+	// Switch to the next or previous tab, wrapping around at the ends
+	KeyBindingNext     KeyBinding
+	KeyBindingPrevious KeyBinding
+
+	// Switch straight to a tab: the first binding shows the first tab and
+	// so on. See AltNumberBindings.
+	KeyBindingsTab []KeyBinding
+
+	// Called after the active tab changes
+	OnTabChanged TabChangedEvent
+
+	active  int
+	focused Focusable
+}
+
+// Alt+1 to Alt+9, for TabView.KeyBindingsTab.
+func AltNumberBindings() []KeyBinding {
+	ret := []KeyBinding{}
+
+	for seq := SeqAlt1; seq <= SeqAlt9; seq++ {
+		ret = append(ret, KeyBinding{Seq: escapebox.Sequence(seq)})
+	}
+
+	return ret
+}
+
+func (t *TabView) GetBounds() *Rect {
+	return &t.Bounds
+}
+
+// The index of the tab currently shown.
+func (t *TabView) Active() int {
+	return t.active
+}
+
+// The tab currently shown, or nil if there are no tabs.
+func (t *TabView) activeTab() *Tab {
+	if t.active < 0 || t.active >= len(t.Tabs) {
+		return nil
+	}
+
+	return t.Tabs[t.active]
+}
+
+// Fit the active tab's Content below the tab strip.
+func (t *TabView) arrange() {
+	tab := t.activeTab()
+	if tab == nil || tab.Content == nil {
+		return
+	}
+
+	tab.Content.Bounds = Rect{
+		Top:    1,
+		Width:  t.Bounds.Width,
+		Height: max(0, t.Bounds.Height-1),
+	}
+}
+
+// Only the active tab's Content takes part in focus and mouse handling.
+func (t *TabView) Children() []Control {
+	tab := t.activeTab()
+	if tab == nil || tab.Content == nil {
+		return []Control{}
+	}
+
+	t.arrange()
+	return []Control{tab.Content}
+}
+
+func (t *TabView) focusChanged(f Focusable) {
+	t.focused = f
+}
+
+// Show the tab at index. If focus was inside the TabView, it moves into the
+// new tab.
+func (t *TabView) SetActive(index int) {
+	t.switchTo(index, false)
+}
+
+func (t *TabView) switchTo(index int, picked bool) {
+	if index < 0 || index >= len(t.Tabs) || index == t.active {
+		return
+	}
+
+	previous := t.activeTab()
+	previousIndex := t.active
+	focusInside := t.focused != nil
+
+	// Focus is changed through the root Container, which is only known
+	// once the TabView has been part of a running MainLoop.
+	var root, parent *Container
+
+	if previous != nil {
+		previous.lastFocus = t.focused
+
+		if previous.Content != nil && previous.Content.parent != nil {
+			parent = previous.Content.parent
+			root = parent.root()
+		}
+	}
+
+	t.active = index
+	t.Invalidate()
+
+	tab := t.activeTab()
+
+	if tab.Content != nil {
+		tab.Content.parent = parent
+		t.arrange()
+		tab.Content.Arrange()
+	}
+
+	if root != nil && (picked || focusInside) {
+		f := t.tabFocus(tab)
+
+		// Don't leave focus on a control which is no longer shown
+		if f != nil || focusInside {
+			root.focus(f)
+		}
+	}
+
+	if t.OnTabChanged != nil {
+		t.OnTabChanged(t, previousIndex)
+	}
+}
+
+// The control to focus when switching to tab, if any.
+func (t *TabView) tabFocus(tab *Tab) Focusable {
+	if tab.Content == nil {
+		return nil
+	}
+
+	return rememberedFocus(tab.Content.Controls, tab.lastFocus)
+}
+
+// Check whether the TabView or the active tab need to be redrawn.
+func (t *TabView) Invalid() bool {
+	if t.Invalidation.Invalid() {
+		return true
+	}
+
+	tab := t.activeTab()
+	return tab != nil && tab.Content != nil && tab.Content.Invalid()
+}
+
+// The left edge and width of each tab's title in the strip.
+func (t *TabView) titlePositions() ([]int, []int) {
+	lefts := make([]int, len(t.Tabs))
+	widths := make([]int, len(t.Tabs))
+
+	left := 0
+
+	for i, tab := range t.Tabs {
+		lefts[i] = left
+		widths[i] = len([]rune(tab.Title)) + 2
+		left += widths[i] + 1
+	}
+
+	return lefts, widths
+}
+
+func (t *TabView) Draw(target *DrawTarget) {
+	lefts, widths := t.titlePositions()
+
+	for i, tab := range t.Tabs {
+		style := theme.Header
+		if i == t.active {
+			style = theme.Selected
+		}
+
+		target.Print(lefts[i], 0, style.Fg, style.Bg, " %s ", tab.Title)
+
+		separator := lefts[i] + widths[i]
+		if separator < t.Bounds.Width {
+			target.SetCell(separator, 0, theme.Border.Fg,
+				theme.Border.Bg, '│')
+		}
+	}
+
+	tab := t.activeTab()
+	if tab == nil || tab.Content == nil {
+		return
+	}
+
+	t.arrange()
+	drawChild(target, tab.Content)
+}
+
+// A TabView never has focus itself, only the controls inside it, so SetFocus
+// and UnsetFocus do nothing. It's Focusable so that it gets the events the
+// controls inside it don't handle.
+func (t *TabView) SetFocus() {
+}
+
+func (t *TabView) UnsetFocus() {
+}
+
+// Switch tabs with the key bindings or by clicking on a title.
+func (t *TabView) HandleEvent(ev escapebox.Event) bool {
+	if leftClick(ev) && ev.MouseY == 0 {
+		lefts, widths := t.titlePositions()
+
+		for i := range t.Tabs {
+			if ev.MouseX >= lefts[i] && ev.MouseX < lefts[i]+widths[i] {
+				t.switchTo(i, true)
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if ev.Type != termbox.EventKey || len(t.Tabs) == 0 {
+		return false
+	}
+
+	if matchBinding(ev, t.KeyBindingNext) {
+		t.switchTo((t.active+1)%len(t.Tabs), true)
+		return true
+	}
+
+	if matchBinding(ev, t.KeyBindingPrevious) {
+		t.switchTo((t.active+len(t.Tabs)-1)%len(t.Tabs), true)
+		return true
+	}
+
+	for i, kb := range t.KeyBindingsTab {
+		if i < len(t.Tabs) && matchBinding(ev, kb) {
+			t.switchTo(i, true)
+			return true
+		}
+	}
+
+	return false
+}
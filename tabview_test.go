@@ -0,0 +1,48 @@
+package tui_test
+
+import (
+	"github.com/briansteffens/tui"
+	"github.com/briansteffens/tui/tuitest"
+	"testing"
+)
+
+func TestTabViewRemembersFocus(t *testing.T) {
+	first := &tui.TextBox{Bounds: tui.Rect{Width: 10, Height: 3}}
+	second := &tui.TextBox{Bounds: tui.Rect{Left: 10, Width: 10,
+		Height: 3}}
+	other := &tui.TextBox{Bounds: tui.Rect{Width: 10, Height: 3}}
+
+	changes := []int{}
+
+	tabs := &tui.TabView{
+		Bounds: tui.Rect{Width: 30, Height: 5},
+		Tabs: []*tui.Tab{
+			{Title: "One", Content: &tui.Container{
+				Controls: []tui.Control{first, second}}},
+			{Title: "Two", Content: &tui.Container{
+				Controls: []tui.Control{other}}},
+		},
+		KeyBindingNext: tui.KeyBinding{Seq: tui.SeqCtrlPageDown},
+		KeyBindingsTab: tui.AltNumberBindings(),
+		OnTabChanged: func(t *tui.TabView, previous int) {
+			changes = append(changes, t.Active())
+		},
+	}
+
+	c := &tui.Container{Controls: []tui.Control{tabs}}
+
+	// Focus starts on second, then moves to other and back to second
+	s := tuitest.Run(t, c, 30, 5, tuitest.Seq(tui.SeqCtrlPageDown),
+		tuitest.Char('x'), tuitest.Seq(tui.SeqAlt1), tuitest.Char('y'))
+
+	tuitest.AssertLine(t, s, 0, " One │ Two │")
+
+	if other.Value != "x" || second.Value != "y" {
+		t.Errorf("expected x and y to be typed into other and second, "+
+			"got %q and %q", other.Value, second.Value)
+	}
+
+	if len(changes) != 2 || changes[0] != 1 || changes[1] != 0 {
+		t.Errorf("unexpected tab changes %v", changes)
+	}
+}
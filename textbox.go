@@ -6,9 +6,14 @@ import (
 )
 
 type TextBox struct {
+	Invalidation
+
 	Bounds Rect
 	Value  string
 
+	// Drawn around the edge, with the text inside it
+	Border BorderStyle
+
 	cursor int
 	scroll int
 	focus  bool
@@ -19,7 +24,7 @@ func (t *TextBox) GetBounds() *Rect {
 }
 
 func (t *TextBox) maxVisibleChars() int {
-	return t.Bounds.Width - 2
+	return max(0, t.Bounds.Width-2)
 }
 
 func (t *TextBox) visibleChars() int {
@@ -31,30 +36,44 @@ func (t *TextBox) lastVisible() int {
 }
 
 func (t *TextBox) Draw(target *DrawTarget) {
-	target.Print(1, 1, termbox.ColorWhite, termbox.ColorBlack,
+	target.Border(t.Border, borderStyle(t.focus), "")
+
+	style := focusStyle(t.focus)
+	target.Print(1, 1, style.Fg, style.Bg,
 		t.Value[t.scroll:t.lastVisible()+1])
 
 	if t.focus {
-		termbox.SetCursor(t.Bounds.Left+1+t.cursor-t.scroll,
-			t.Bounds.Top+1)
+		target.SetCursor(1+t.cursor-t.scroll, 1)
 	}
 }
 
 func (t *TextBox) SetFocus() {
 	t.focus = true
+	t.Invalidate()
 }
 
 func (t *TextBox) UnsetFocus() {
 	t.focus = false
+	t.Invalidate()
 }
 
 func (t *TextBox) HandleEvent(ev escapebox.Event) bool {
 	pre := t.Value[0:t.cursor]
 	post := t.Value[t.cursor:len(t.Value)]
 
+	oldValue := t.Value
+	oldCursor := t.cursor
+	oldScroll := t.scroll
+
 	handled := false
 
 	switch ev.Type {
+	case termbox.EventMouse:
+		if leftClick(ev) {
+			// Text starts one cell in from the left edge
+			t.cursor = t.scroll + ev.MouseX - 1
+			handled = true
+		}
 	case termbox.EventKey:
 		char := string(ev.Ch)
 
@@ -102,8 +121,15 @@ func (t *TextBox) HandleEvent(ev escapebox.Event) bool {
 		t.scroll = t.cursor
 	}
 
-	if t.cursor >= t.scroll+t.maxVisibleChars() {
-		t.scroll = t.cursor - t.maxVisibleChars() + 1
+	// A TextBox too narrow to show any text still scrolls as if it could
+	// show one character, so scroll never passes the cursor.
+	if visible := max(1, t.maxVisibleChars()); t.cursor >= t.scroll+visible {
+		t.scroll = t.cursor - visible + 1
+	}
+
+	if t.Value != oldValue || t.cursor != oldCursor ||
+		t.scroll != oldScroll {
+		t.Invalidate()
 	}
 
 	return handled
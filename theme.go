@@ -0,0 +1,130 @@
+package tui
+
+// The colors and attributes used to draw something. Attributes like
+// AttrBold are OR'ed into Fg.
+type Style struct {
+	Fg Color
+	Bg Color
+}
+
+// A Theme is the set of named Styles the controls draw with. Controls look
+// the current theme up every time they draw, so it can be swapped at runtime
+// with SetTheme.
+type Theme struct {
+	// Text and controls without focus
+	Normal Style
+
+	// The control which has focus
+	Focused Style
+
+	// Controls or items which can't currently be used
+	Disabled Style
+
+	// The selected item or cell, like the DetailView cursor
+	Selected Style
+
+	// Column headers and titles
+	Header Style
+
+	// Borders drawn around dialogs, frames and controls, and the border of
+	// the control which has focus, or the frame around it
+	Border        Style
+	FocusedBorder Style
+
+	// The shadow dialogs cast on what's underneath
+	Shadow Style
+
+	// DetailView rows, which alternate between Row and AltRow
+	Row    Style
+	AltRow Style
+
+	// Syntax highlighting in an EditBox
+	Keyword Style
+	Type    Style
+	String  Style
+
+	// Lines selected in an EditBox's visual line mode
+	VisualSelection Style
+
+	// Mode and notice lines, like EditBox's "-- INSERT --"
+	StatusLine Style
+
+	// The characters of ComboBox items which match the typed text. Only
+	// the foreground is used, over the item's background.
+	Match Style
+
+	// MenuBars and open menus. The highlighted item uses Selected and
+	// disabled items the foreground of Disabled.
+	Menu Style
+}
+
+// The theme for dark terminals, and the default.
+func DefaultTheme() *Theme {
+	return &Theme{
+		Normal:          Style{ColorWhite, ColorBlack},
+		Focused:         Style{ColorWhite, ColorBlack},
+		Disabled:        Style{ColorDarkGray, ColorBlack},
+		Selected:        Style{ColorWhite, ColorBlue},
+		Header:          Style{ColorWhite | AttrBold, ColorBlack},
+		Border:          Style{ColorWhite, ColorBlack},
+		FocusedBorder:   Style{ColorLightCyan, ColorBlack},
+		Shadow:          Style{ColorDefault, ColorDarkGray},
+		Row:             Style{ColorWhite, ColorDefault},
+		AltRow:          Style{ColorWhite, ColorDefault},
+		Keyword:         Style{ColorBlue, ColorBlack},
+		Type:            Style{ColorRed, ColorBlack},
+		String:          Style{ColorGreen, ColorBlack},
+		VisualSelection: Style{ColorBlack, ColorYellow},
+		StatusLine:      Style{ColorDefault, ColorDefault},
+		Match:           Style{ColorLightYellow | AttrBold, ColorBlack},
+		Menu:            Style{ColorBlack, ColorLightGray},
+	}
+}
+
+// A theme for terminals with a light background.
+func LightTheme() *Theme {
+	return &Theme{
+		Normal:          Style{ColorBlack, ColorWhite},
+		Focused:         Style{ColorBlack, ColorWhite},
+		Disabled:        Style{ColorDarkGray, ColorWhite},
+		Selected:        Style{ColorWhite, ColorBlue},
+		Header:          Style{ColorBlack | AttrBold, ColorWhite},
+		Border:          Style{ColorBlack, ColorWhite},
+		FocusedBorder:   Style{ColorBlue, ColorWhite},
+		Shadow:          Style{ColorDefault, ColorDarkGray},
+		Row:             Style{ColorBlack, ColorWhite},
+		AltRow:          Style{ColorBlack, ColorLightGray},
+		Keyword:         Style{ColorBlue, ColorWhite},
+		Type:            Style{ColorMagenta, ColorWhite},
+		String:          Style{ColorGreen, ColorWhite},
+		VisualSelection: Style{ColorBlack, ColorLightYellow},
+		StatusLine:      Style{ColorDefault, ColorDefault},
+		Match:           Style{ColorRed | AttrBold, ColorWhite},
+		Menu:            Style{ColorBlack, ColorLightGray},
+	}
+}
+
+var theme = DefaultTheme()
+
+// Set when the theme changes, so the next Refresh redraws everything
+var themeChanged bool
+
+// Switch to a different theme. Everything is redrawn on the next Refresh.
+func SetTheme(t *Theme) {
+	theme = t
+	themeChanged = true
+}
+
+// Get the theme controls are currently drawn with.
+func CurrentTheme() *Theme {
+	return theme
+}
+
+// Pick the style for a control depending on whether it has focus.
+func focusStyle(focus bool) Style {
+	if focus {
+		return theme.Focused
+	}
+
+	return theme.Normal
+}
@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"sync"
+	"time"
+)
+
+// A Timer runs a function on the UI goroutine after a delay, or repeatedly
+// at an interval. The screen is refreshed after each run, redrawing the
+// controls the function invalidated, just like with Post. Create one with
+// AfterFunc or Every.
+type Timer struct {
+	f        func()
+	interval time.Duration
+
+	lock    sync.Mutex
+	timer   stopper
+	stopped bool
+}
+
+// Something which can be stopped, like a time.Timer
+type stopper interface {
+	Stop() bool
+}
+
+// Run f on its own goroutine once d has elapsed. Tests replace this with a
+// clock they control.
+var afterFunc = func(d time.Duration, f func()) stopper {
+	return time.AfterFunc(d, f)
+}
+
+// Run f on the UI goroutine once d has elapsed.
+func AfterFunc(d time.Duration, f func()) *Timer {
+	t := &Timer{f: f}
+	t.start(d)
+	return t
+}
+
+// Run f on the UI goroutine every d until the Timer is stopped. The next run
+// is scheduled once the previous one has finished, so runs never pile up
+// behind a busy UI goroutine.
+func Every(d time.Duration, f func()) *Timer {
+	t := &Timer{f: f, interval: d}
+	t.start(d)
+	return t
+}
+
+// Cancel the Timer. Once Stop returns, f will not be started again, even if it
+// was already due. Stop is safe to call from any goroutine and more than once.
+func (t *Timer) Stop() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.stopped = true
+	t.timer.Stop()
+}
+
+func (t *Timer) start(d time.Duration) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.stopped {
+		return
+	}
+
+	t.timer = afterFunc(d, func() {
+		Post(t.run)
+	})
+}
+
+// Called on the UI goroutine when the Timer fires.
+func (t *Timer) run() {
+	t.lock.Lock()
+	stopped := t.stopped
+	t.lock.Unlock()
+
+	if stopped {
+		return
+	}
+
+	t.f()
+
+	if t.interval > 0 {
+		t.start(t.interval)
+	}
+}
@@ -0,0 +1,92 @@
+package tui_test
+
+import (
+	"fmt"
+	"github.com/briansteffens/escapebox"
+	"github.com/briansteffens/tui"
+	"github.com/briansteffens/tui/tuitest"
+	"testing"
+	"time"
+)
+
+func TestAfterFunc(t *testing.T) {
+	clock, restore := tui.UseFakeClock()
+	defer restore()
+
+	label := &tui.Label{Bounds: tui.Rect{Width: 20, Height: 1},
+		Text: "waiting"}
+	runs := 0
+
+	c := &tui.Container{
+		Controls: []tui.Control{label},
+		EventHandler: func(c *tui.Container, ev escapebox.Event) bool {
+			switch ev.Ch {
+			case 's':
+				tui.AfterFunc(time.Second, func() {
+					runs++
+					label.Text = "fired"
+					label.Invalidate()
+				})
+			case 'f':
+				clock.Advance(time.Second / 2)
+			}
+			return true
+		},
+	}
+
+	// Nothing happens until a second has passed, and then only once
+	s := tuitest.Run(t, c, 20, 1, tuitest.Char('s'), tuitest.Char('f'),
+		tuitest.Char('f'), tuitest.WaitForInterrupt(), tuitest.Char('f'),
+		tuitest.Char('f'))
+
+	tuitest.AssertLine(t, s, 0, "fired")
+
+	// One refresh at the start and one after the Timer ran
+	if runs != 1 || s.Flushes() != 2 {
+		t.Errorf("expected a single run and refresh, got %d runs and "+
+			"%d flushes", runs, s.Flushes())
+	}
+}
+
+func TestEveryAndStop(t *testing.T) {
+	clock, restore := tui.UseFakeClock()
+	defer restore()
+
+	label := &tui.Label{Bounds: tui.Rect{Width: 20, Height: 1}}
+	runs := 0
+
+	var timer *tui.Timer
+
+	c := &tui.Container{
+		Controls: []tui.Control{label},
+		EventHandler: func(c *tui.Container, ev escapebox.Event) bool {
+			switch ev.Ch {
+			case 'e':
+				timer = tui.Every(time.Second, func() {
+					runs++
+					label.Text = fmt.Sprintf("%d runs", runs)
+					label.Invalidate()
+				})
+			case 'f':
+				clock.Advance(time.Second)
+			case 'x':
+				// The Timer comes due, but is stopped before
+				// it gets to run
+				clock.Advance(time.Second)
+				timer.Stop()
+			}
+			return true
+		},
+	}
+
+	s := tuitest.Run(t, c, 20, 1, tuitest.Char('e'), tuitest.Char('f'),
+		tuitest.WaitForInterrupt(), tuitest.Char('f'),
+		tuitest.WaitForInterrupt(), tuitest.Char('x'),
+		tuitest.WaitForInterrupt(), tuitest.Char('f'))
+
+	tuitest.AssertLine(t, s, 0, "2 runs")
+
+	if runs != 2 {
+		t.Errorf("expected 2 runs, got %d", runs)
+	}
+}
@@ -0,0 +1,240 @@
+package tuitest
+
+import (
+	"flag"
+	"fmt"
+	"github.com/briansteffens/escapebox"
+	"github.com/briansteffens/tui"
+	"github.com/nsf/termbox-go"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var _ tui.Screen = (*Screen)(nil)
+
+var updateGolden = flag.Bool("update-golden", false,
+	"rewrite tuitest golden files instead of comparing against them")
+
+// A key press such as termbox.KeyTab or termbox.KeyArrowDown.
+func Key(key termbox.Key) escapebox.Event {
+	return escapebox.Event{
+		Event: termbox.Event{Type: termbox.EventKey, Key: key},
+	}
+}
+
+// A key press of a printable character.
+func Char(ch rune) escapebox.Event {
+	return escapebox.Event{
+		Event: termbox.Event{Type: termbox.EventKey, Ch: ch},
+	}
+}
+
+// A non-standard escape sequence registered with escapebox, such as
+// tui.SeqShiftTab.
+func Seq(seq escapebox.Sequence) escapebox.Event {
+	return escapebox.Event{
+		Event: termbox.Event{Type: termbox.EventKey},
+		Seq:   seq,
+	}
+}
+
+// A mouse event at screen coordinates (x, y). key is one of the termbox
+// Mouse* constants, such as termbox.MouseWheelDown.
+func Mouse(key termbox.Key, x, y int) escapebox.Event {
+	return escapebox.Event{
+		Event: termbox.Event{
+			Type:   termbox.EventMouse,
+			Key:    key,
+			MouseX: x,
+			MouseY: y,
+		},
+	}
+}
+
+// A left click at screen coordinates (x, y).
+func Click(x, y int) escapebox.Event {
+	return Mouse(termbox.MouseLeft, x, y)
+}
+
+// The mouse moving to screen coordinates (x, y) with the left button held
+// down. termbox reports this as termbox.MouseLeft with termbox.ModMotion set.
+func Drag(x, y int) escapebox.Event {
+	ev := Mouse(termbox.MouseLeft, x, y)
+	ev.Mod = termbox.ModMotion
+	return ev
+}
+
+// The terminal being resized to width x height.
+func Resize(width, height int) escapebox.Event {
+	return escapebox.Event{
+		Event: termbox.Event{
+			Type:   termbox.EventResize,
+			Width:  width,
+			Height: height,
+		},
+	}
+}
+
+// Wait for something to call the Screen's Interrupt, like tui.Post or a
+// tui.Timer, and deliver the interrupt to MainLoop, which then runs what was
+// posted. MainLoop fails if nothing interrupts within five seconds.
+func WaitForInterrupt() escapebox.Event {
+	return escapebox.Event{
+		Event: termbox.Event{Type: termbox.EventInterrupt},
+	}
+}
+
+// The key presses needed to type text. Spaces are sent as termbox.KeySpace
+// and newlines as termbox.KeyEnter, the way termbox reports them.
+func Type(text string) []escapebox.Event {
+	ret := []escapebox.Event{}
+
+	for _, r := range text {
+		switch r {
+		case ' ':
+			ret = append(ret, Key(termbox.KeySpace))
+		case '\n':
+			ret = append(ret, Key(termbox.KeyEnter))
+		default:
+			ret = append(ret, Char(r))
+		}
+	}
+
+	return ret
+}
+
+// Run c through tui.MainLoop on a new width x height Screen, feeding it
+// events until they run out. The Screen is returned for inspection. t fails
+// immediately if MainLoop returns an error.
+func Run(t testing.TB, c *tui.Container, width, height int,
+	events ...escapebox.Event) *Screen {
+	t.Helper()
+
+	s := NewScreen(width, height, events...)
+
+	previous := tui.CurrentScreen()
+	tui.SetScreen(s)
+	defer tui.SetScreen(previous)
+
+	if err := tui.MainLoop(c); err != nil {
+		t.Fatalf("MainLoop: %v", err)
+	}
+
+	return s
+}
+
+// A grid with one letter per cell naming that cell's foreground/background
+// combination, followed by a legend of the combinations. Letters are handed
+// out in order of first appearance, scanning row by row.
+func (s *Screen) Styles() string {
+	const names = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ" +
+		"0123456789"
+
+	type style struct {
+		fg, bg tui.Color
+	}
+
+	letters := map[style]byte{}
+	legend := []string{}
+
+	grid := make([]string, s.height)
+
+	for y := 0; y < s.height; y++ {
+		row := make([]byte, s.width)
+
+		for x := 0; x < s.width; x++ {
+			cell := s.Cell(x, y)
+			st := style{cell.Fg, cell.Bg}
+
+			letter, ok := letters[st]
+			if !ok {
+				letter = '?'
+				if len(letters) < len(names) {
+					letter = names[len(letters)]
+				}
+
+				letters[st] = letter
+				legend = append(legend, fmt.Sprintf(
+					"%c fg=%v bg=%v", letter, st.fg, st.bg))
+			}
+
+			row[x] = letter
+		}
+
+		grid[y] = string(row)
+	}
+
+	return strings.Join(grid, "\n") + "\n\n" + strings.Join(legend, "\n")
+}
+
+// The text, cursor and styles of the Screen in one human-readable document.
+// This is what golden files contain.
+func (s *Screen) Snapshot() string {
+	x, y, visible := s.Cursor()
+
+	cursor := "hidden"
+	if visible {
+		cursor = fmt.Sprintf("%d,%d", x, y)
+	}
+
+	return fmt.Sprintf("%s\n\n-- cursor: %s\n\n%s\n", s.Text(), cursor,
+		s.Styles())
+}
+
+// Compare the Screen's Snapshot to testdata/<name>.golden, failing t if they
+// differ. Run the tests with -update-golden to write the current Snapshot
+// to the file instead.
+func AssertGolden(t testing.TB, s *Screen, name string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	actual := s.Snapshot()
+
+	if *updateGolden {
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err == nil {
+			err = os.WriteFile(path, []byte(actual), 0644)
+		}
+
+		if err != nil {
+			t.Fatalf("updating golden file: %v", err)
+		}
+
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file (run with -update-golden to "+
+			"create it): %v", err)
+	}
+
+	if string(expected) != actual {
+		t.Errorf("screen does not match %s\n\nexpected:\n%s\n"+
+			"actual:\n%s", path, expected, actual)
+	}
+}
+
+// Fail t unless the text at row y, with trailing spaces removed, is
+// expected.
+func AssertLine(t testing.TB, s *Screen, y int, expected string) {
+	t.Helper()
+
+	if actual := s.Line(y); actual != expected {
+		t.Errorf("line %d: expected %q, got %q", y, expected, actual)
+	}
+}
+
+// Fail t unless the cell at (x, y) has the given colors.
+func AssertStyle(t testing.TB, s *Screen, x, y int,
+	fg, bg tui.Color) {
+	t.Helper()
+
+	cell := s.Cell(x, y)
+	if cell.Fg != fg || cell.Bg != bg {
+		t.Errorf("cell %d,%d: expected fg=%v bg=%v, got fg=%v bg=%v",
+			x, y, fg, bg, cell.Fg, cell.Bg)
+	}
+}
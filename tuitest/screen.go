@@ -0,0 +1,238 @@
+// Package tuitest runs tui screens headlessly. A Screen is an in-memory
+// replacement for the terminal which replays a scripted sequence of input
+// events and records what was drawn, so the result can be checked as plain
+// text, per-cell colors or against golden files.
+package tuitest
+
+import (
+	"errors"
+	"github.com/briansteffens/escapebox"
+	"github.com/briansteffens/tui"
+	"github.com/nsf/termbox-go"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// One character cell on a Screen.
+type Cell struct {
+	Ch rune
+	Fg tui.Color
+	Bg tui.Color
+}
+
+var emptyCell = Cell{Ch: ' ', Fg: tui.ColorDefault, Bg: tui.ColorDefault}
+
+// An in-memory tui.Screen. Drawing goes to a back buffer which is copied to
+// the visible front buffer on Flush, just like a real terminal.
+type Screen struct {
+	width  int
+	height int
+	back   []Cell
+	front  []Cell
+
+	cursorX       int
+	cursorY       int
+	cursorVisible bool
+
+	events  []escapebox.Event
+	flushes int
+
+	interruptLock sync.Mutex
+	interrupts    int
+
+	// Signalled by Interrupt, for PollEvent to wait on
+	interrupted chan struct{}
+}
+
+// How long a scripted interrupt waits for Interrupt to be called
+const interruptTimeout = 5 * time.Second
+
+// Create a Screen of the given size which will feed the given events to
+// PollEvent, in order. Once they run out, PollEvent reports io.EOF, which
+// makes tui.MainLoop return.
+func NewScreen(width, height int, events ...escapebox.Event) *Screen {
+	s := &Screen{events: events, interrupted: make(chan struct{}, 1)}
+	s.resize(width, height)
+	return s
+}
+
+func (s *Screen) resize(width, height int) {
+	s.width = width
+	s.height = height
+	s.back = make([]Cell, width*height)
+	s.front = make([]Cell, width*height)
+
+	for i := range s.back {
+		s.back[i] = emptyCell
+		s.front[i] = emptyCell
+	}
+}
+
+func (s *Screen) inBounds(x, y int) bool {
+	return x >= 0 && x < s.width && y >= 0 && y < s.height
+}
+
+func (s *Screen) SetCell(x, y int, ch rune, fg, bg tui.Color) {
+	if !s.inBounds(x, y) {
+		return
+	}
+
+	s.back[y*s.width+x] = Cell{Ch: ch, Fg: fg, Bg: bg}
+}
+
+func (s *Screen) SetCursor(x, y int) {
+	s.cursorX = x
+	s.cursorY = y
+	s.cursorVisible = true
+}
+
+func (s *Screen) HideCursor() {
+	s.cursorVisible = false
+}
+
+func (s *Screen) Size() (int, int) {
+	return s.width, s.height
+}
+
+func (s *Screen) Flush() error {
+	copy(s.front, s.back)
+	s.flushes++
+	return nil
+}
+
+// Return the next scripted event. Resize events also resize the Screen, the
+// way the terminal would have been resized before reporting them. Pending
+// interrupts are reported before any scripted events, and a scripted
+// interrupt waits for Interrupt to be called.
+func (s *Screen) PollEvent() escapebox.Event {
+	interrupt := escapebox.Event{
+		Event: termbox.Event{Type: termbox.EventInterrupt},
+	}
+
+	if len(s.events) > 0 && s.events[0].Type == termbox.EventInterrupt {
+		s.events = s.events[1:]
+
+		if !s.waitForInterrupt() {
+			return escapebox.Event{Event: termbox.Event{
+				Type: termbox.EventError,
+				Err:  errors.New("timed out waiting for an interrupt"),
+			}}
+		}
+
+		return interrupt
+	}
+
+	if s.takeInterrupt() {
+		return interrupt
+	}
+
+	if len(s.events) == 0 {
+		return escapebox.Event{
+			Event: termbox.Event{Type: termbox.EventError, Err: io.EOF},
+		}
+	}
+
+	ev := s.events[0]
+	s.events = s.events[1:]
+
+	if ev.Type == termbox.EventResize {
+		s.resize(ev.Width, ev.Height)
+	}
+
+	return ev
+}
+
+// Use up a pending interrupt, if there is one.
+func (s *Screen) takeInterrupt() bool {
+	s.interruptLock.Lock()
+	defer s.interruptLock.Unlock()
+
+	if s.interrupts == 0 {
+		return false
+	}
+
+	s.interrupts--
+	return true
+}
+
+// Wait for an interrupt and use it up. Returns false if none arrives in time.
+func (s *Screen) waitForInterrupt() bool {
+	timeout := time.After(interruptTimeout)
+
+	for !s.takeInterrupt() {
+		select {
+		case <-s.interrupted:
+		case <-timeout:
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *Screen) Interrupt() {
+	s.interruptLock.Lock()
+	s.interrupts++
+	s.interruptLock.Unlock()
+
+	select {
+	case s.interrupted <- struct{}{}:
+	default:
+	}
+}
+
+// Queue more events to be returned by PollEvent. Like the rest of the Screen,
+// except Interrupt, this is only safe to call from the UI goroutine.
+func (s *Screen) Push(events ...escapebox.Event) {
+	s.events = append(s.events, events...)
+}
+
+// The visible contents of the cell at (x, y). Out of bounds cells are blank.
+func (s *Screen) Cell(x, y int) Cell {
+	if !s.inBounds(x, y) {
+		return emptyCell
+	}
+
+	return s.front[y*s.width+x]
+}
+
+// The cursor position and whether it is currently shown.
+func (s *Screen) Cursor() (int, int, bool) {
+	return s.cursorX, s.cursorY, s.cursorVisible
+}
+
+// The number of times Flush has been called.
+func (s *Screen) Flushes() int {
+	return s.flushes
+}
+
+// The visible text of the Screen, one line per row with trailing spaces
+// removed.
+func (s *Screen) Text() string {
+	lines := make([]string, s.height)
+
+	for y := 0; y < s.height; y++ {
+		row := make([]rune, s.width)
+
+		for x := 0; x < s.width; x++ {
+			row[x] = s.Cell(x, y).Ch
+		}
+
+		lines[y] = strings.TrimRight(string(row), " ")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// The line of visible text at row y, with trailing spaces removed.
+func (s *Screen) Line(y int) string {
+	lines := strings.Split(s.Text(), "\n")
+
+	if y < 0 || y >= len(lines) {
+		return ""
+	}
+
+	return lines[y]
+}
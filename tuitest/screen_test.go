@@ -0,0 +1,95 @@
+package tuitest
+
+import (
+	"github.com/briansteffens/escapebox"
+	"github.com/briansteffens/tui"
+	"github.com/nsf/termbox-go"
+	"io"
+	"testing"
+)
+
+func TestScreenReplaysEvents(t *testing.T) {
+	s := NewScreen(10, 5, Char('a'), Resize(20, 8))
+	s.Interrupt()
+
+	// Interrupts come before the scripted events
+	if ev := s.PollEvent(); ev.Type != termbox.EventInterrupt {
+		t.Errorf("expected an interrupt, got %+v", ev)
+	}
+
+	if ev := s.PollEvent(); ev.Ch != 'a' {
+		t.Errorf("expected 'a', got %+v", ev)
+	}
+
+	s.PollEvent()
+
+	if width, height := s.Size(); width != 20 || height != 8 {
+		t.Errorf("expected the resize to apply, got %dx%d", width,
+			height)
+	}
+
+	s.Push(Key(termbox.KeyEnter))
+
+	if ev := s.PollEvent(); ev.Key != termbox.KeyEnter {
+		t.Errorf("expected the pushed event, got %+v", ev)
+	}
+
+	if ev := s.PollEvent(); ev.Type != termbox.EventError ||
+		ev.Err != io.EOF {
+		t.Errorf("expected io.EOF once the events ran out, got %+v", ev)
+	}
+}
+
+func TestScreenFlush(t *testing.T) {
+	s := NewScreen(4, 2)
+
+	s.SetCell(1, 0, 'x', tui.ColorRed, tui.ColorBlue)
+	s.SetCell(9, 9, 'y', tui.ColorRed, tui.ColorBlue)
+
+	// Nothing shows until it's flushed
+	if s.Cell(1, 0).Ch != ' ' {
+		t.Errorf("expected the cell to be blank before Flush")
+	}
+
+	s.Flush()
+
+	AssertLine(t, s, 0, " x")
+	AssertStyle(t, s, 1, 0, tui.ColorRed, tui.ColorBlue)
+
+	if s.Flushes() != 1 || s.Cell(9, 9) != emptyCell {
+		t.Errorf("unexpected flushes %d or out of bounds cell %+v",
+			s.Flushes(), s.Cell(9, 9))
+	}
+}
+
+func TestType(t *testing.T) {
+	events := Type("a b\n")
+	expected := []escapebox.Event{Char('a'), Key(termbox.KeySpace),
+		Char('b'), Key(termbox.KeyEnter)}
+
+	if len(events) != len(expected) {
+		t.Fatalf("expected %d events, got %d", len(expected),
+			len(events))
+	}
+
+	for i := range events {
+		if events[i] != expected[i] {
+			t.Errorf("event %d: expected %+v, got %+v", i,
+				expected[i], events[i])
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	label := &tui.Label{Bounds: tui.Rect{Left: 1, Top: 1, Width: 8,
+		Height: 1}, Text: "Hello"}
+	box := &tui.TextBox{Bounds: tui.Rect{Top: 2, Width: 10, Height: 3}}
+
+	c := &tui.Container{Controls: []tui.Control{label, box}}
+
+	s := Run(t, c, 10, 5, Type("hi")...)
+
+	AssertLine(t, s, 1, " Hello")
+	AssertLine(t, s, 3, " hi")
+	AssertGolden(t, s, "run")
+}
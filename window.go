@@ -0,0 +1,419 @@
+package tui
+
+import (
+	"github.com/briansteffens/escapebox"
+	"github.com/nsf/termbox-go"
+	"sort"
+)
+
+// A Window is a Container floating above the root Container's controls, shown
+// with ShowWindow. Unlike a Dialog it doesn't take over the input: the rest of
+// the screen can still be used while it's open, and any number of Windows can
+// be open at once. Bounds sets the position and size of the area inside the
+// border, in screen coordinates.
+//
+// Each Window has its own focus chain. The focus bindings stay inside the
+// Window which has focus, and switching between Windows with the root
+// Container's KeyBindingWindowNext brings focus back to where it was. Focusing
+// anything in a Window raises it above the others.
+type Window struct {
+	Container
+
+	// Shown in the top border
+	Title string
+
+	// The smallest size the inside of the Window can be resized to
+	MinWidth  int
+	MinHeight int
+
+	// What had focus in the Window when focus last left it
+	lastFocus Focusable
+
+	// Position in the stacking order: higher is drawn on top
+	z int
+}
+
+// What the arrow keys do to the focused Window.
+type windowMode int
+
+const (
+	windowModeNone windowMode = iota
+	windowModeMove
+	windowModeResize
+)
+
+// Show w above everything else on the screen and move focus into it.
+func (c *Container) ShowWindow(w *Window) {
+	root := c.root()
+
+	w.parent = root
+	root.windows = append(root.windows, w)
+
+	w.clamp()
+	w.Arrange()
+
+	root.focusWindow(w)
+}
+
+// Remove the Window from the screen. If it had focus, focus goes to the
+// Window below it, or back to the root Container's controls.
+func (w *Window) Close() {
+	root := w.root()
+	hadFocus := w.Focused != nil
+
+	for i, open := range root.windows {
+		if open == w {
+			root.windows = append(root.windows[:i],
+				root.windows[i+1:]...)
+			break
+		}
+	}
+
+	if root.moving == w {
+		root.windowMode = windowModeNone
+		root.moving = nil
+	}
+
+	w.parent = nil
+
+	if hadFocus {
+		var next *Window
+		if stack := root.stacked(); len(stack) > 0 {
+			next = stack[len(stack)-1]
+		}
+
+		root.focusWindow(next)
+	}
+
+	// Uncover what was underneath
+	root.Invalidate()
+}
+
+// Draw the Window above all other Windows.
+func (w *Window) Raise() {
+	root := w.root()
+
+	for _, open := range root.windows {
+		if open != w && open.z >= w.z {
+			w.z = open.z + 1
+		}
+	}
+
+	w.Invalidate()
+}
+
+// Move the Window by dx columns and dy rows. The top-left corner always stays
+// on the screen.
+func (w *Window) Move(dx, dy int) {
+	w.Bounds.Left += dx
+	w.Bounds.Top += dy
+	w.clamp()
+
+	w.root().Invalidate()
+}
+
+// Resize the inside of the Window by dw columns and dh rows, down to its
+// minimum size.
+func (w *Window) Resize(dw, dh int) {
+	w.Bounds.Width = max(w.Bounds.Width+dw, max(1, w.MinWidth))
+	w.Bounds.Height = max(w.Bounds.Height+dh, max(1, w.MinHeight))
+	w.Arrange()
+
+	w.root().Invalidate()
+}
+
+// Keep the Window's border on the screen and its size above the minimum.
+// Until the root Container has been given a size there's no screen to keep
+// the Window on, so it stays where it is until MainLoop arranges the root.
+func (w *Window) clamp() {
+	root := w.root()
+
+	w.Bounds.Width = max(w.Bounds.Width, max(1, w.MinWidth))
+	w.Bounds.Height = max(w.Bounds.Height, max(1, w.MinHeight))
+
+	if root != &w.Container && root.Bounds.Width > 0 &&
+		root.Bounds.Height > 0 {
+		w.Bounds.Left = min(w.Bounds.Left, root.Bounds.Width-1)
+		w.Bounds.Top = min(w.Bounds.Top, root.Bounds.Height-1)
+	}
+
+	w.Bounds.Left = max(1, w.Bounds.Left)
+	w.Bounds.Top = max(1, w.Bounds.Top)
+}
+
+// The Window's area including its border.
+func (w *Window) frame() *Rect {
+	return &Rect{
+		Left:   w.Bounds.Left - 1,
+		Top:    w.Bounds.Top - 1,
+		Width:  w.Bounds.Width + 2,
+		Height: w.Bounds.Height + 2,
+	}
+}
+
+// The open Windows from the bottom of the stacking order to the top.
+func (c *Container) stacked() []*Window {
+	ret := append([]*Window{}, c.windows...)
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].z < ret[j].z
+	})
+
+	return ret
+}
+
+// The Window which has focus, if any.
+func (c *Container) focusedWindow() *Window {
+	for _, w := range c.root().windows {
+		if w.Focused != nil {
+			return w
+		}
+	}
+
+	return nil
+}
+
+// The control to focus when coming back to controls: last, if it's still
+// there, or otherwise the first Focusable control.
+func rememberedFocus(controls []Control, last Focusable) Focusable {
+	controls = leaves(controls)
+
+	if last != nil {
+		for _, ctrl := range controls {
+			if ctrl == last {
+				return last
+			}
+		}
+	}
+
+	for _, ctrl := range controls {
+		if f, ok := ctrl.(Focusable); ok {
+			return f
+		}
+	}
+
+	return nil
+}
+
+// Move focus into w where it last was, or back to the root Container's
+// controls if w is nil.
+func (c *Container) focusWindow(w *Window) {
+	root := c.root()
+
+	if w == nil {
+		root.focus(rememberedFocus(root.Controls, root.mainFocus))
+		return
+	}
+
+	if f := rememberedFocus(w.Controls, w.lastFocus); f != nil {
+		root.focus(f)
+	} else {
+		// Nothing to focus, but the Window should still come to the top
+		root.focus(nil)
+		w.Raise()
+	}
+}
+
+// Remember where focus was before it moves, so it can be restored when
+// switching back to the Window or the root Container's controls.
+func (c *Container) rememberFocus() {
+	if c.Focused == nil {
+		return
+	}
+
+	if w := c.focusedWindow(); w != nil {
+		w.lastFocus = c.Focused
+	} else if _, ok := ancestorsIn(c.Controls, c.Focused); ok {
+		c.mainFocus = c.Focused
+	}
+}
+
+// Move focus to the next Window in the order they were opened, then back to
+// the root Container's controls.
+func (c *Container) focusNextWindow() {
+	root := c.root()
+	current := root.focusedWindow()
+
+	next := 0
+	for i, w := range root.windows {
+		if w == current {
+			next = i + 1
+		}
+	}
+
+	if next < len(root.windows) {
+		root.focusWindow(root.windows[next])
+	} else {
+		root.focusWindow(nil)
+	}
+}
+
+// Handle the root Container's Window key bindings, and the arrow keys while
+// moving or resizing a Window.
+func (c *Container) handleWindowKey(ev escapebox.Event) bool {
+	if ev.Type != termbox.EventKey {
+		return false
+	}
+
+	if c.windowMode != windowModeNone {
+		return c.handleWindowMode(ev)
+	}
+
+	w := c.focusedWindow()
+
+	switch {
+	case matchBinding(ev, c.KeyBindingWindowNext) && len(c.windows) > 0:
+		c.focusNextWindow()
+	case w == nil:
+		return false
+	case matchBinding(ev, c.KeyBindingWindowClose):
+		w.Close()
+	case matchBinding(ev, c.KeyBindingWindowMove):
+		c.startWindowMode(w, windowModeMove)
+	case matchBinding(ev, c.KeyBindingWindowResize):
+		c.startWindowMode(w, windowModeResize)
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (c *Container) startWindowMode(w *Window, mode windowMode) {
+	c.windowMode = mode
+	c.moving = w
+	w.Invalidate()
+}
+
+// Move or resize a Window with the arrow keys until Enter or Esc is pressed,
+// or the binding which started it is pressed again. Other keys are ignored
+// until then.
+func (c *Container) handleWindowMode(ev escapebox.Event) bool {
+	w := c.moving
+
+	dx, dy := 0, 0
+
+	switch ev.Key {
+	case termbox.KeyArrowUp:
+		dy = -1
+	case termbox.KeyArrowDown:
+		dy = 1
+	case termbox.KeyArrowLeft:
+		dx = -1
+	case termbox.KeyArrowRight:
+		dx = 1
+	case termbox.KeyEnter, termbox.KeyEsc:
+		c.windowMode = windowModeNone
+	}
+
+	if matchBinding(ev, c.KeyBindingWindowMove) ||
+		matchBinding(ev, c.KeyBindingWindowResize) {
+		c.windowMode = windowModeNone
+	}
+
+	switch {
+	case c.windowMode == windowModeNone:
+		c.moving = nil
+		w.Invalidate()
+	case dx == 0 && dy == 0:
+	case c.windowMode == windowModeMove:
+		w.Move(dx, dy)
+	default:
+		w.Resize(dx, dy)
+	}
+
+	return true
+}
+
+// The Window which gets a mouse event: one dragging something inside it, or
+// otherwise the topmost one under the pointer.
+func (c *Container) windowAt(x, y int) *Window {
+	for _, w := range c.windows {
+		if w.captured != nil {
+			return w
+		}
+	}
+
+	stack := c.stacked()
+
+	for i := len(stack) - 1; i >= 0; i-- {
+		if stack[i].frame().ContainsPoint(x, y) {
+			return stack[i]
+		}
+	}
+
+	return nil
+}
+
+// Dispatch a mouse event to the controls inside the Window. Clicking anywhere
+// on the Window brings focus into it.
+func (w *Window) handleMouse(ev escapebox.Event) bool {
+	root := w.root()
+
+	if w.Bounds.ContainsPoint(ev.MouseX, ev.MouseY) || w.captured != nil {
+		local := ev
+		local.MouseX -= w.Bounds.Left
+		local.MouseY -= w.Bounds.Top
+
+		w.Container.handleMouse(local)
+	}
+
+	if leftClick(ev) && root.focusedWindow() != w {
+		root.focusWindow(w)
+	}
+
+	// Whatever is underneath is covered
+	return true
+}
+
+// The border style of a Window, showing whether it has focus or is being
+// moved or resized.
+func (w *Window) borderStyle() Style {
+	if root := w.root(); root.moving == w {
+		return theme.Selected
+	}
+
+	return borderStyle(w.Focused != nil)
+}
+
+// Draw the open Windows in stacking order on top of the Container's controls.
+// dirty holds the areas which were drawn over so far: a Window overlapping any
+// of them is redrawn completely, and then covers its own area. Returns the
+// areas drawn over, including the Windows.
+func (c *Container) drawWindows(target *DrawTarget, dirty []Rect) []Rect {
+	for _, w := range c.stacked() {
+		frame := w.frame()
+
+		for i := range dirty {
+			if frame.Intersects(&dirty[i]) {
+				w.Invalidate()
+				break
+			}
+		}
+
+		if !w.Invalid() {
+			continue
+		}
+
+		if w.Invalidation.Invalid() {
+			border, err := target.Slice(frame)
+			if err != nil {
+				target.ReportError(err)
+				continue
+			}
+
+			border.Border(BorderSingle, w.borderStyle(), w.Title)
+		}
+
+		inner, err := target.Slice(&w.Bounds)
+		if err != nil {
+			target.ReportError(err)
+			continue
+		}
+
+		w.Container.Draw(inner)
+		dirty = append(dirty, *frame)
+	}
+
+	return dirty
+}
@@ -0,0 +1,103 @@
+package tui_test
+
+import (
+	"github.com/briansteffens/escapebox"
+	"github.com/briansteffens/tui"
+	"github.com/briansteffens/tui/tuitest"
+	"github.com/nsf/termbox-go"
+	"testing"
+)
+
+func TestWindowStacking(t *testing.T) {
+	main := &tui.TextBox{Bounds: tui.Rect{Width: 40, Height: 3}}
+	first := &tui.TextBox{Bounds: tui.Rect{Width: 20, Height: 3}}
+	second := &tui.TextBox{Bounds: tui.Rect{Width: 20, Height: 3}}
+
+	a := &tui.Window{Title: "A", Container: tui.Container{
+		Bounds:   tui.Rect{Left: 2, Top: 2, Width: 20, Height: 3},
+		Controls: []tui.Control{first},
+	}}
+	b := &tui.Window{Title: "B", Container: tui.Container{
+		Bounds:   tui.Rect{Left: 10, Top: 4, Width: 20, Height: 3},
+		Controls: []tui.Control{second},
+	}}
+
+	c := &tui.Container{
+		Controls:             []tui.Control{main},
+		KeyBindingWindowNext: tui.KeyBinding{Key: termbox.KeyF6},
+		KeyBindingWindowMove: tui.KeyBinding{Key: termbox.KeyF7},
+		EventHandler: func(c *tui.Container, ev escapebox.Event) bool {
+			switch ev.Key {
+			case termbox.KeyF2:
+				c.ShowWindow(a)
+			case termbox.KeyF3:
+				c.ShowWindow(b)
+			default:
+				return false
+			}
+			return true
+		},
+	}
+
+	// B opens on top of A, then switching back to A through the main
+	// TextBox raises it again.
+	s := tuitest.Run(t, c, 40, 12, tuitest.Key(termbox.KeyF2),
+		tuitest.Char('a'), tuitest.Key(termbox.KeyF3), tuitest.Char('b'),
+		tuitest.Key(termbox.KeyF6), tuitest.Char('m'),
+		tuitest.Key(termbox.KeyF6), tuitest.Char('c'))
+
+	if main.Value != "m" || first.Value != "ac" || second.Value != "b" {
+		t.Errorf("unexpected values %q, %q and %q", main.Value,
+			first.Value, second.Value)
+	}
+
+	// A's bottom border covers the inside of B
+	if cell := s.Cell(15, 5); cell.Ch != '─' {
+		t.Errorf("expected A's border on top of B, got %q", cell.Ch)
+	}
+
+	tuitest.AssertStyle(t, s, 15, 5, tui.ColorLightCyan, tui.ColorBlack)
+
+	// Starting MainLoop again focuses the main TextBox. Move B up and to
+	// the left, over A.
+	s = tuitest.Run(t, c, 40, 12, tuitest.Key(termbox.KeyF6),
+		tuitest.Key(termbox.KeyF6), tuitest.Key(termbox.KeyF7),
+		tuitest.Key(termbox.KeyArrowUp), tuitest.Key(termbox.KeyArrowLeft),
+		tuitest.Key(termbox.KeyEnter), tuitest.Char('x'))
+
+	if *b.GetBounds() != (tui.Rect{Left: 9, Top: 3, Width: 20,
+		Height: 3}) || second.Value != "bx" {
+		t.Errorf("unexpected bounds %+v and value %q", *b.GetBounds(),
+			second.Value)
+	}
+
+	tuitest.AssertLine(t, s, 4, " │      │ bx                 │")
+}
+
+func TestShowWindowBeforeMainLoop(t *testing.T) {
+	placed := &tui.Window{Title: "Placed", Container: tui.Container{
+		Bounds: tui.Rect{Left: 20, Top: 5, Width: 10, Height: 2},
+	}}
+	offscreen := &tui.Window{Title: "Off", Container: tui.Container{
+		Bounds: tui.Rect{Left: 100, Top: 50, Width: 10, Height: 2},
+	}}
+
+	c := &tui.Container{}
+	c.ShowWindow(placed)
+	c.ShowWindow(offscreen)
+
+	// Windows keep their position until the screen size is known, and are
+	// then kept on the screen
+	tuitest.Run(t, c, 40, 12)
+
+	if placed.Bounds != (tui.Rect{Left: 20, Top: 5, Width: 10,
+		Height: 2}) {
+		t.Errorf("expected the Window to stay put, got %+v",
+			placed.Bounds)
+	}
+
+	if offscreen.Bounds.Left != 39 || offscreen.Bounds.Top != 11 {
+		t.Errorf("expected the Window to be moved on screen, got %+v",
+			offscreen.Bounds)
+	}
+}